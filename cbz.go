@@ -0,0 +1,299 @@
+package img
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+)
+
+const comicInfoName = "ComicInfo.xml"
+
+// comicInfo is the subset of the ComicRack ComicInfo.xml schema that can be
+// filled from Dublin Core. The other elements of a decoded ComicInfo.xml are
+// kept in Other.
+type comicInfo struct {
+	XMLName   xml.Name           `xml:"ComicInfo"`
+	Title     string             `xml:"Title,omitempty"`
+	Summary   string             `xml:"Summary,omitempty"`
+	Writer    string             `xml:"Writer,omitempty"`
+	Tags      string             `xml:"Tags,omitempty"`
+	PageCount int                `xml:"PageCount,omitempty"`
+	Other     []comicInfoElement `xml:",any"`
+}
+
+type comicInfoElement struct {
+	XMLName xml.Name
+	Inner   string `xml:",innerxml"`
+}
+
+func (ci comicInfo) dublinCore() xmp.DublinCore {
+	dc := xmp.DublinCore{
+		Title:   splitComicInfo(ci.Title),
+		Creator: splitComicInfo(ci.Writer),
+		Subject: splitComicInfo(ci.Tags),
+	}
+	if ci.Summary != "" {
+		dc.Description = []string{ci.Summary}
+	}
+	return dc
+}
+
+func splitComicInfo(s string) []string {
+	var vals []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+func newComicInfo(dc xmp.DublinCore, pages int) comicInfo {
+	return comicInfo{
+		Title:     strings.Join(dc.Title, ", "),
+		Summary:   strings.Join(dc.Description, "\n"),
+		Writer:    strings.Join(dc.Creator, ", "),
+		Tags:      strings.Join(dc.Subject, ", "),
+		PageCount: pages,
+	}
+}
+
+// encodeCBZ writes pages to a zip archive named with the encoder's padding
+// and page format, followed by a ComicInfo.xml if one was set.
+func (enc *Encoder) encodeCBZ(w io.Writer, pages []image.Image) error {
+	zw := zip.NewWriter(w)
+	pageEnc := *enc
+	pageEnc.Format = enc.cbzPageFormat
+	pageEnc.pages = nil
+	for i, page := range pages {
+		name := fmt.Sprintf(enc.padding, i) + pageEnc.Format.String()
+		pw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		err = pageEnc.Encode(pw, page)
+		if err != nil {
+			return err
+		}
+	}
+	if enc.comicInfo != nil {
+		err := writeComicInfo(zw, newComicInfo(*enc.comicInfo, len(pages)))
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeComicInfo(zw *zip.Writer, ci comicInfo) error {
+	cw, err := zw.Create(comicInfoName)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(cw, xml.Header)
+	if err != nil {
+		return err
+	}
+	xe := xml.NewEncoder(cw)
+	xe.Indent("", "  ")
+	return xe.Encode(ci)
+}
+
+// embedComicInfo rewrites a comic archive with a ComicInfo.xml made from dc,
+// or none if dc is nil, copying the pages without recompressing them. The
+// elements of the old ComicInfo.xml that don't come from Dublin Core are
+// kept.
+func embedComicInfo(data []byte, dc *xmp.DublinCore) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		buf bytes.Buffer
+		old comicInfo
+	)
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if f.Name != comicInfoName {
+			err = zw.Copy(f)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		err = xml.NewDecoder(r).Decode(&old)
+		r.Close()
+		if err != nil {
+			return nil, &CorruptError{Offset: -1, Err: err}
+		}
+	}
+	if dc != nil {
+		ci := newComicInfo(*dc, len(cbzPages(zr)))
+		ci.Other = old.Other
+		err = writeComicInfo(zw, ci)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cbzReader opens a comic archive. The whole archive is read into memory
+// unless r is already a *bytes.Reader.
+func cbzReader(r io.Reader) (*zip.Reader, error) {
+	if br, ok := r.(*bytes.Reader); ok {
+		return zip.NewReader(br, br.Size())
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(b), int64(len(b)))
+}
+
+// cbzPages returns the names of the images in a comic archive in natural
+// sort order, so that page2 comes before page10.
+func cbzPages(zr *zip.Reader) []string {
+	var pages []string
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		if strings.HasPrefix(path.Base(f.Name), ".") || !IsValidFormat(f.Name) {
+			continue
+		}
+		if ext, _ := Ext(f.Name); ext == CBZ.String() {
+			continue
+		}
+		pages = append(pages, f.Name)
+	}
+	slices.SortFunc(pages, naturalCompare)
+	return pages
+}
+
+// decodeCBZ decodes the pages of a comic archive, or only the first unless
+// all is set.
+func decodeCBZ(r io.Reader, all bool, opts ...DecodeOption) ([]image.Image, error) {
+	zr, err := cbzReader(r)
+	if err != nil {
+		return nil, err
+	}
+	pages := cbzPages(zr)
+	if len(pages) == 0 {
+		return nil, ErrCorrupt
+	}
+	if !all {
+		pages = pages[:1]
+	}
+	return OpenAllFS(zr, pages, opts...)
+}
+
+// decodeCBZMeta reads the Dublin Core from a comic archive's ComicInfo.xml.
+func decodeCBZMeta(r io.Reader) (xmp.XMP, error) {
+	x := xmp.XMP{DC: xmp.DublinCore{}}
+	zr, err := cbzReader(r)
+	if err != nil {
+		return x, err
+	}
+	f, err := zr.Open(comicInfoName)
+	if err != nil {
+		return x, ErrNoMetadata
+	}
+	defer f.Close()
+	var ci comicInfo
+	err = xml.NewDecoder(f).Decode(&ci)
+	if err != nil {
+		return x, &CorruptError{Offset: -1, Err: err}
+	}
+	x.DC = ci.dublinCore()
+	return x, nil
+}
+
+func decodeCBZConfig(r io.Reader) (image.Config, error) {
+	zr, err := cbzReader(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	pages := cbzPages(zr)
+	if len(pages) == 0 {
+		return image.Config{}, ErrCorrupt
+	}
+	f, err := zr.Open(pages[0])
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	pageFmt, err := FormatFromFilename(pages[0])
+	if err != nil {
+		return image.Config{}, err
+	}
+	return pageFmt.DecodeConfig(f)
+}
+
+func countCBZPages(b []byte) (int, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return 0, err
+	}
+	return len(cbzPages(zr)), nil
+}
+
+// naturalCompare compares strings treating runs of digits as numbers.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		da, db := isDigit(a[0]), isDigit(b[0])
+		switch {
+		case da && db:
+			na, ra := splitDigits(a)
+			nb, rb := splitDigits(b)
+			if c := compareDigits(na, nb); c != 0 {
+				return c
+			}
+			a, b = ra, rb
+		case a[0] != b[0]:
+			return strings.Compare(a[:1], b[:1])
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareDigits compares two runs of digits by value, ignoring leading
+// zeros.
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,191 @@
+package img
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+	qt "github.com/frankban/quicktest"
+)
+
+func TestNaturalSort(t *testing.T) {
+	c := qt.New(t)
+	names := []string{"page10.jpg", "page2.jpg", "page1.jpg", "cover.jpg", "page02b.jpg"}
+	slices.SortFunc(names, naturalCompare)
+	c.Assert(names, qt.DeepEquals, []string{"cover.jpg", "page1.jpg", "page2.jpg", "page02b.jpg", "page10.jpg"})
+}
+
+func TestCBZ(t *testing.T) {
+	c := qt.New(t)
+	pages := make([]image.Image, 12)
+	for i := range pages {
+		pages[i] = image.NewGray(image.Rect(0, 0, 10+i, 20))
+	}
+	dc := xmp.DublinCore{
+		Title:   []string{"Comic"},
+		Creator: []string{"Writer"},
+		Subject: []string{"one", "two"},
+	}
+	out := filepath.Join(t.TempDir(), "book.cbz")
+	err := SaveAll(out, pages, Padding("%d"), CBZPageFormat(PNG), ComicInfo(dc))
+	c.Assert(err, qt.IsNil)
+
+	zr, err := zip.OpenReader(out)
+	c.Assert(err, qt.IsNil)
+	defer zr.Close()
+	c.Assert(zr.File, qt.HasLen, 13)
+	c.Assert(zr.File[12].Name, qt.Equals, "ComicInfo.xml")
+
+	i, err := Open(out, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.Fmt, qt.Equals, CBZ)
+	c.Assert(i.DublinCore().Title, qt.DeepEquals, dc.Title)
+	c.Assert(i.DublinCore().Subject, qt.DeepEquals, dc.Subject)
+
+	data, err := os.ReadFile(out)
+	c.Assert(err, qt.IsNil)
+	all, err := CBZ.DecodeAll(bytes.NewReader(data))
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 12)
+	for n, page := range all {
+		c.Assert(page.Bounds().Dx(), qt.Equals, 10+n)
+	}
+
+	_, err = CBZ.Decode(bytes.NewReader(data), MaxFrames(10))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	_, err = CBZ.DecodeAll(bytes.NewReader(data), MaxFrames(10))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	// the first page fits, but the later ones are wider
+	_, err = CBZ.DecodeAll(bytes.NewReader(data), MaxWidth(15))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+
+	copied := filepath.Join(t.TempDir(), "copy.cbz")
+	c.Assert(ConvertAll(out, copied), qt.IsNil)
+	i, err = Open(copied, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Creator, qt.DeepEquals, dc.Creator)
+}
+
+func TestConvertAllPDF(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	pages := make([]image.Image, 3)
+	for i := range pages {
+		pages[i] = image.NewGray(image.Rect(0, 0, 10+i, 20))
+	}
+	book := filepath.Join(dir, "book.cbz")
+	c.Assert(SaveAll(book, pages, CBZPageFormat(PNG)), qt.IsNil)
+
+	pdfName := filepath.Join(dir, "book.pdf")
+	c.Assert(ConvertAll(book, pdfName), qt.IsNil)
+	entries, err := os.ReadDir(dir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 2)
+	f, err := os.Open(pdfName)
+	c.Assert(err, qt.IsNil)
+	all, err := PDF.DecodeAll(f)
+	f.Close()
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 3)
+	b, err := os.ReadFile(pdfName)
+	c.Assert(err, qt.IsNil)
+	_, err = PDF.DecodeAll(bytes.NewReader(b), MaxWidth(11))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+
+	back := filepath.Join(dir, "back.cbz")
+	c.Assert(ConvertAll(pdfName, back), qt.IsNil)
+	data, err := os.ReadFile(back)
+	c.Assert(err, qt.IsNil)
+	all, err = CBZ.DecodeAll(bytes.NewReader(data))
+	c.Assert(err, qt.IsNil)
+	c.Assert(all, qt.HasLen, 3)
+	for n, page := range all {
+		c.Assert(page.Bounds().Dx(), qt.Equals, 10+n)
+	}
+}
+
+func TestCBZSaveMeta(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	book := filepath.Join(dir, "book.cbz")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	pages := map[string][]byte{}
+	for _, name := range []string{"p1.png", "p2.png", "p3.png"} {
+		var page bytes.Buffer
+		c.Assert(png.Encode(&page, image.NewGray(image.Rect(0, 0, 10+len(pages), 20))), qt.IsNil)
+		pages[name] = page.Bytes()
+		w, err := zw.Create(name)
+		c.Assert(err, qt.IsNil)
+		_, err = w.Write(page.Bytes())
+		c.Assert(err, qt.IsNil)
+	}
+	w, err := zw.Create(comicInfoName)
+	c.Assert(err, qt.IsNil)
+	_, err = io.WriteString(w, "<ComicInfo><Title>Old</Title><Series>Saga</Series></ComicInfo>")
+	c.Assert(err, qt.IsNil)
+	c.Assert(zw.Close(), qt.IsNil)
+	c.Assert(os.WriteFile(book, buf.Bytes(), 0644), qt.IsNil)
+
+	// the pages are copied as they were
+	assertPages := func() *zip.ReadCloser {
+		zr, err := zip.OpenReader(book)
+		c.Assert(err, qt.IsNil)
+		c.Assert(cbzPages(&zr.Reader), qt.HasLen, 3)
+		for name, want := range pages {
+			f, err := zr.Open(name)
+			c.Assert(err, qt.IsNil)
+			got, err := io.ReadAll(f)
+			f.Close()
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, want)
+		}
+		return zr
+	}
+
+	i, err := Open(book, true)
+	c.Assert(err, qt.IsNil)
+	i.SetTitle("New")
+	tags := NewTags()
+	tags.Add("A", "B")
+	i.SetTags(tags)
+	c.Assert(i.Save(), qt.IsNil)
+	zr := assertPages()
+	f, err := zr.Open(comicInfoName)
+	c.Assert(err, qt.IsNil)
+	info, err := io.ReadAll(f)
+	f.Close()
+	zr.Close()
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(info), qt.Contains, "<Title>New</Title>")
+	c.Assert(string(info), qt.Contains, "<Series>Saga</Series>")
+	c.Assert(string(info), qt.Contains, "<PageCount>3</PageCount>")
+	i, err = Open(book, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Subject, qt.DeepEquals, []string{"A", "B"})
+
+	c.Assert(i.Save(StripMeta(StripAll)), qt.IsNil)
+	zr = assertPages()
+	c.Assert(zr.File, qt.HasLen, 3)
+	zr.Close()
+
+	// a PDF can't be saved in place without re-encoding it
+	pdfName := filepath.Join(dir, "book.pdf")
+	c.Assert(ConvertAll(book, pdfName), qt.IsNil)
+	before, err := os.ReadFile(pdfName)
+	c.Assert(err, qt.IsNil)
+	i, err = Open(pdfName, true)
+	c.Assert(err, qt.IsNil)
+	i.SetTitle("New")
+	c.Assert(i.Save(), qt.ErrorIs, ErrMultiPage)
+	after, err := os.ReadFile(pdfName)
+	c.Assert(err, qt.IsNil)
+	c.Assert(after, qt.DeepEquals, before)
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var mergeFields []string
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply [flags] sidecars...",
+	Short: "write metadata from yaml or json files back into the images",
+	Long: `Apply reads the files written by each, slice or --batch and saves the
+values into the image named by each file, or identifier if there is no
+file. Fields missing from a file are left alone. Fields are replaced unless
+they are listed with --merge, in which case the new values are added to the
+existing ones. The title made up from the file name of an untitled image
+isn't written back.
+
+Subjects may be written as paths, such as "Places > New York City".`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, f := range mergeFields {
+			if !slices.Contains(sidecarFields, f) {
+				log.Fatalf("can't merge %q, fields are %s", f, strings.Join(sidecarFields, ", "))
+			}
+		}
+		for _, name := range args {
+			err := applySidecar(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringSliceVar(&mergeFields, "merge", nil, "fields to merge rather than replace: "+strings.Join(sidecarFields, ", "))
+	addEditFlags(applyCmd)
+}
+
+var sidecarFields = []string{"title", "creator", "description", "rights", "subject"}
+
+// sidecar is the editable part of the metadata written by the other
+// commands. A nil field wasn't in the file.
+type sidecar struct {
+	Identifier  string    `json:"identifier" yaml:"identifier"`
+	File        string    `json:"file" yaml:"file"`
+	Title       *[]string `json:"title" yaml:"title"`
+	Creator     *[]string `json:"creator" yaml:"creator"`
+	Description *[]string `json:"description" yaml:"description"`
+	Rights      *[]string `json:"rights" yaml:"rights"`
+	Subject     *[]string `json:"subject" yaml:"subject"`
+}
+
+// readSidecars reads a single sidecar or a batch of them from a yaml or json
+// file.
+func readSidecars(name string) ([]sidecar, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimSpace(b)
+	var cars []sidecar
+	switch filepath.Ext(name) {
+	case ".json":
+		if bytes.HasPrefix(b, []byte("[")) {
+			err = json.Unmarshal(b, &cars)
+		} else {
+			cars = make([]sidecar, 1)
+			err = json.Unmarshal(b, &cars[0])
+		}
+	default:
+		// batches are written as a sequence, each image as a mapping
+		if bytes.HasPrefix(b, []byte("-")) && !bytes.HasPrefix(b, []byte("---")) {
+			err = yaml.Unmarshal(b, &cars)
+		} else {
+			cars = make([]sidecar, 1)
+			err = yaml.NewDecoder(bytes.NewReader(b)).Decode(&cars[0])
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return cars, nil
+}
+
+func applySidecar(name string) error {
+	cars, err := readSidecars(name)
+	if err != nil {
+		return err
+	}
+	for _, car := range cars {
+		id := car.File
+		if id == "" {
+			id = car.Identifier
+		}
+		file, err := sidecarImage(name, id)
+		if err != nil {
+			return err
+		}
+		err = editImages([]string{file}, car.apply)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sidecarImage finds the image named by id, which is relative to the
+// working directory when the sidecar was written, or next to the sidecar.
+func sidecarImage(name, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("%s: missing identifier", name)
+	}
+	candidates := []string{id}
+	if !filepath.IsAbs(id) {
+		dir := filepath.Dir(name)
+		candidates = append(candidates, filepath.Join(dir, id), filepath.Join(dir, filepath.Base(id)))
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("%s: no image found for %q", name, id)
+}
+
+func (car sidecar) apply(i *img.Img) error {
+	dc := i.DublinCore()
+	if car.Title != nil && !isFileTitle(i, dc.Title, *car.Title) {
+		i.SetTitle(applyField("title", dc.Title, *car.Title)...)
+	}
+	if car.Creator != nil {
+		i.SetCreator(applyField("creator", dc.Creator, *car.Creator)...)
+	}
+	if car.Description != nil {
+		i.SetDescription(applyField("description", dc.Description, *car.Description)...)
+	}
+	if car.Rights != nil {
+		i.SetRights(applyField("rights", dc.Rights, *car.Rights)...)
+	}
+	if car.Subject != nil {
+		i.SetTags(applySubject(i.Tags(), *car.Subject, slices.Contains(mergeFields, "subject")))
+	}
+	return nil
+}
+
+// isFileTitle reports whether title is the one made up from the file name
+// when the other commands describe an untitled image, which isn't written
+// back.
+func isFileTitle(i *img.Img, old, title []string) bool {
+	name := filepath.Base(i.Path())
+	return len(old) == 0 && slices.Equal(title, []string{strings.TrimSuffix(name, filepath.Ext(name))})
+}
+
+func applyField(field string, old, vals []string) []string {
+	vals = nonEmpty(vals)
+	if !slices.Contains(mergeFields, field) {
+		return vals
+	}
+	out := slices.Clone(old)
+	for _, v := range vals {
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// applySubject updates the tag tree from a flat subject list. Replacing keeps
+// the existing paths whose leaf is still listed so the hierarchy isn't lost.
+func applySubject(tags *img.Tags, subject []string, merge bool) *img.Tags {
+	out := img.NewTags()
+	listed := map[string]bool{}
+	for _, s := range subject {
+		listed[s] = true
+	}
+	used := map[string]bool{}
+	for _, p := range tags.Paths() {
+		if !merge && !listed[p[len(p)-1]] {
+			continue
+		}
+		out.Add(p...)
+		for _, name := range p {
+			used[name] = true
+		}
+	}
+	for _, s := range nonEmpty(subject) {
+		p := img.FromPaths([]string{s}, img.Separator).Paths()
+		if len(p) == 0 || (len(p[0]) == 1 && used[s]) {
+			continue
+		}
+		out.Add(p[0]...)
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+// clearCmd represents the clear command
+var clearCmd = &cobra.Command{
+	Use:   "clear images...",
+	Short: "remove the title, creator, description and tags",
+	Args:  minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := editImages(args, func(i *img.Img) error {
+			i.ClearMeta()
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clearCmd)
+	addEditFlags(clearCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyFrom   string
+	copyFields []string
+)
+
+// copyCmd represents the copy command
+var copyCmd = &cobra.Command{
+	Use:   "copy --from src images...",
+	Short: "copy metadata from one image to others",
+	Long: `Copy writes the metadata of --from into each image without
+re-encoding it. Without --fields the title, creator, rights, description,
+tags and EXIF block are all copied.`,
+	Args: minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var fields []img.ExifField
+		for _, name := range copyFields {
+			f, ok := copyFieldNames[name]
+			if !ok {
+				log.Fatalf("unknown field %q, use title, creator, rights, description or tags", name)
+			}
+			fields = append(fields, f)
+		}
+		names, err := metaImages(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = img.CopyMeta(copyFrom, names, fields...)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var copyFieldNames = map[string]img.ExifField{
+	"title":       img.Title,
+	"creator":     img.Byline,
+	"rights":      img.Rights,
+	"description": img.Description,
+	"tags":        img.Subject,
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+	copyCmd.Flags().StringVar(&copyFrom, "from", "", "image to copy the metadata from")
+	copyCmd.Flags().StringSliceVar(&copyFields, "fields", nil, "only copy these fields: title, creator, rights, description, tags")
+	copyCmd.MarkFlagRequired("from")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff a b",
+	Short: "compare the metadata of two images",
+	Long: `Diff prints the title, creator, description, rights and tags that
+change from a to b, and exits with 1 if there are any.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		imgs, err := decodeManyMuchMeta(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		changes := img.DiffMeta(imgs[0], imgs[1])
+		for _, c := range changes {
+			fmt.Printf("%s:\n", fieldLabel(c.Field))
+			if c.Field == img.HierarchicalSubject {
+				printValues("-", c.Removed())
+				printValues("+", c.Added())
+				continue
+			}
+			printValues("-", c.From)
+			printValues("+", c.To)
+		}
+		if len(changes) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+func printValues(prefix string, vals []string) {
+	for _, v := range vals {
+		fmt.Printf("%s %s\n", prefix, strings.ReplaceAll(v, "\n", "\n  "))
+	}
+}
+
+// fieldLabel names a field as it appears in the yaml and json output.
+func fieldLabel(f img.ExifField) string {
+	switch f {
+	case img.Byline:
+		return "creator"
+	case img.HierarchicalSubject:
+		return "tags"
+	}
+	return strings.ToLower(f.String())
+}
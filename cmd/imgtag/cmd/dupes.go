@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dupeHash     string
+	dupeDistance int
+	dupeJobs     int
+)
+
+// dupesCmd represents the dupes command
+var dupesCmd = &cobra.Command{
+	Use:   "dupes [flags] dirs or images...",
+	Short: "find near duplicate images",
+	Long: `Dupes hashes the images, walking any directories, and prints groups of
+images whose perceptual hashes differ by at most --distance bits. The images
+in each group are listed largest first, with their distance from the first.
+Unlike the other commands, directories are walked recursively unless
+--recursive=false is given.`,
+	Args: minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, ok := map[string]img.HashKind{"a": img.AHash, "d": img.DHash, "p": img.PHash}[dupeHash]
+		if !ok {
+			log.Fatalf("unknown hash %q, use a, d or p", dupeHash)
+		}
+		if !cmd.Flags().Changed("recursive") {
+			recursive = true
+		}
+		files, err := listImages(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		imgs := hashImages(files, dupeJobs)
+		groups := dupeGroups(imgs, kind, dupeDistance)
+		err = encodeMeta(os.Stdout, groups)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dupesCmd)
+	dupesCmd.Flags().StringVar(&dupeHash, "hash", "p", "hash to compare: a, d or p")
+	dupesCmd.Flags().IntVar(&dupeDistance, "distance", 10, "maximum number of differing bits")
+	dupesCmd.Flags().IntVar(&dupeJobs, "jobs", runtime.NumCPU(), "images to hash at once")
+}
+
+type dupeImage struct {
+	Identifier string             `json:"identifier" yaml:"identifier"`
+	Width      int                `json:"width" yaml:"width"`
+	Height     int                `json:"height" yaml:"height"`
+	Size       int64              `json:"size" yaml:"size"`
+	Distance   int                `json:"distance" yaml:"distance"`
+	Hash       img.PerceptualHash `json:"hash" yaml:"hash"`
+}
+
+// hashImages hashes files with jobs workers, keeping their order. Files that
+// can't be hashed are skipped with a warning.
+func hashImages(files []string, jobs int) []dupeImage {
+	out := make([]dupeImage, len(files))
+	errs := make([]error, len(files))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range max(jobs, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				out[k], errs[k] = hashImage(files[k])
+			}
+		}()
+	}
+	for k := range files {
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+	var hashed []dupeImage
+	for k, err := range errs {
+		if err != nil {
+			log.Printf("skipping %s: %v", files[k], err)
+			continue
+		}
+		hashed = append(hashed, out[k])
+	}
+	return hashed
+}
+
+func hashImage(name string) (dupeImage, error) {
+	d := dupeImage{Identifier: name}
+	i, err := img.New(name)
+	if err != nil {
+		return d, err
+	}
+	d.Hash, err = i.PerceptualHash()
+	if err != nil {
+		return d, err
+	}
+	cfg, err := i.Config()
+	if err != nil {
+		return d, err
+	}
+	d.Width, d.Height = cfg.Width, cfg.Height
+	info, err := os.Stat(name)
+	if err != nil {
+		return d, err
+	}
+	d.Size = info.Size()
+	return d, nil
+}
+
+// dupeGroups clusters the images, listing the largest of each group first.
+func dupeGroups(imgs []dupeImage, kind img.HashKind, dist int) [][]dupeImage {
+	hashes := make([]img.PerceptualHash, len(imgs))
+	for k, d := range imgs {
+		hashes[k] = d.Hash
+	}
+	var groups [][]dupeImage
+	for _, idx := range img.GroupDuplicates(hashes, kind, dist) {
+		var g []dupeImage
+		for _, k := range idx {
+			g = append(g, imgs[k])
+		}
+		slices.SortStableFunc(g, func(a, b dupeImage) int {
+			return b.Width*b.Height - a.Width*a.Height
+		})
+		for k := range g {
+			g[k].Distance = g[k].Hash.Get(kind).Distance(g[0].Hash.Get(kind))
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
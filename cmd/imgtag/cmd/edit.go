@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun  bool
+	editDir string
+)
+
+// addEditFlags adds the flags shared by the commands that modify images. The
+// local --output shadows the persistent one, naming a directory rather than
+// a metadata file.
+func addEditFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the changes without saving")
+	cmd.Flags().StringVarP(&editDir, "output", "o", "", "save the edited images to this directory instead of in place")
+}
+
+// editImages applies edit to the metadata of each image listed by args and
+// saves it, or prints a diff of the changes with --dry-run. Images edited in
+// place are only rewritten if their metadata changed.
+func editImages(args []string, edit func(*img.Img) error) error {
+	names, err := metaImages(args)
+	if err != nil {
+		return err
+	}
+	if editDir != "" && !dryRun {
+		err := os.MkdirAll(editDir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		i, err := decodeMeta(name)
+		if err != nil {
+			return err
+		}
+		// a document id given by --id uuid is saved with the edit
+		newID := i.MetaChanged()
+		before, err := dcYAML(i)
+		if err != nil {
+			return err
+		}
+		err = edit(i)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		after, err := dcYAML(i)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			printDiff(os.Stdout, name, before, after)
+			continue
+		}
+		if editDir == "" {
+			if before == after && !newID {
+				continue
+			}
+			err = i.Save()
+		} else {
+			err = i.SaveAs(filepath.Join(editDir, filepath.Base(name)))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dcYAML renders the metadata that the edit commands change: the Dublin
+// Core, without an unset date, and the tag paths, which the flat subject
+// doesn't show.
+func dcYAML(i *img.Img) (string, error) {
+	dc := i.DublinCore()
+	v, err := plainValue(dc)
+	if err != nil {
+		return "", err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		m = map[string]any{}
+	}
+	if dc.Date.IsZero() {
+		delete(m, "date")
+	}
+	if tags := i.Tags().Join(img.Separator); len(tags) > 0 {
+		m["tags"] = tags
+	}
+	var buf bytes.Buffer
+	err = encodeYAML(&buf, m)
+	return buf.String(), err
+}
+
+// printDiff writes a line diff of before and after in unified style,
+// without hunk headers.
+func printDiff(w io.Writer, name, before, after string) {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", name, name)
+	for _, l := range diffLines(a, b) {
+		fmt.Fprintln(w, l)
+	}
+}
+
+// diffLines returns the lines of a and b prefixed with "-", "+" or " ",
+// using their longest common subsequence.
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	return lines
+}
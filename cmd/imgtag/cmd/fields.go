@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// outputFields are the fields of imgMeta that --fields selects from.
+var outputFields = []string{
+	"title",
+	"creator",
+	"description",
+	"rights",
+	"subject",
+	"date",
+	"format",
+	"width",
+	"height",
+	"colorModel",
+	"gps",
+}
+
+var (
+	fieldList []string
+	// shownFields is the selection made by --fields and the field flags. It
+	// is empty when every field is shown.
+	shownFields = map[string]bool{}
+)
+
+// parseFields reads the selection from --fields and the --title, --creator,
+// --description and --subject flags.
+func parseFields(flags *pflag.FlagSet) error {
+	names := fieldList
+	for _, name := range []string{"title", "creator", "description", "subject"} {
+		if on, _ := flags.GetBool(name); on {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if strings.EqualFold(name, "tags") {
+			name = "subject"
+		}
+		found := false
+		for _, f := range outputFields {
+			if strings.EqualFold(name, f) {
+				shownFields[f] = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field %q, use %s", name, strings.Join(outputFields, ", "))
+		}
+	}
+	return nil
+}
+
+// showField reports whether the field is to be decoded and written.
+func showField(name string) bool {
+	return len(shownFields) == 0 || shownFields[name]
+}
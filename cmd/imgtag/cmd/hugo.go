@@ -1,17 +1,30 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/ohzqq/img"
+	"github.com/pelletier/go-toml"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
 var (
-	hugoOutput string
-	hugoTitle  string
+	hugoOutput      string
+	hugoTitle       string
+	hugoWidths      []int
+	hugoFormats     []string
+	hugoBundle      bool
+	hugoFrontMatter string
+	hugoResourceFmt string
+	hugoTemplate    string
 )
 
 // hugoCmd represents the hugo command
@@ -19,29 +32,24 @@ var hugoCmd = &cobra.Command{
 	Use:     "hugo",
 	Aliases: []string{"h"},
 	Short:   "write hugo front matter",
+	Long: `Hugo writes a post listing the images as resources, with the union of their
+tags and the date of the earliest photo. The post is named after --name, or
+with --bundle is the index.md of a page bundle that the images are copied
+into, converted to --resource-format if it is set.
+
+With --srcset each image is also resized to the given widths in each
+--srcset-format, and its variants and <picture> html are added to the
+resource's params.
+
+--template is a text/template for the post. It is given .Title, .Date,
+.Tags, .Resources, .Images and the rendered .FrontMatter, and defaults to
+just the front matter.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		out := lo.KebabCase(strings.ToLower(hugoTitle)) + ".md"
-		tags := []string{}
-		metas, err := metaSlice(args)
-		if err != nil {
-			log.Fatal(err)
-		}
-		for _, params := range metas {
-			tags = append(tags, params.Subject...)
-		}
-		tags = lo.Uniq(tags)
-		m := map[string]any{
-			"title": hugoTitle,
-			"tags":  tags,
-			"params": map[string]any{
-				"images": metas,
-			},
-		}
-		w, err := os.Create(out)
+		page, err := newHugoPage(args)
 		if err != nil {
 			log.Fatal(err)
 		}
-		err = encodeMeta(w, m)
+		err = page.write()
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -51,4 +59,261 @@ var hugoCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(hugoCmd)
 	hugoCmd.Flags().StringVarP(&hugoTitle, "name", "n", "title", "name of post")
+	hugoCmd.Flags().StringVarP(&hugoOutput, "output", "o", ".", "directory to write the post to")
+	hugoCmd.Flags().BoolVar(&hugoBundle, "bundle", false, "write a page bundle with the images as resources")
+	hugoCmd.Flags().StringVar(&hugoResourceFmt, "resource-format", "", "convert the bundled images to this format")
+	hugoCmd.Flags().StringVar(&hugoFrontMatter, "front-matter", "yaml", "yaml, toml or json")
+	hugoCmd.Flags().StringVar(&hugoTemplate, "template", "", "text/template file for the post")
+	hugoCmd.Flags().IntSliceVar(&hugoWidths, "srcset", nil, "widths to resize the images to")
+	hugoCmd.Flags().StringSliceVar(&hugoFormats, "srcset-format", []string{"webp", "jpg"}, "formats of the resized images, the last is the fallback")
+}
+
+// hugoPage is the data given to --template.
+type hugoPage struct {
+	Title       string
+	Date        time.Time
+	Tags        []string
+	Resources   []hugoResource
+	Images      []imgMeta
+	FrontMatter string
+	// dir is where the post and any bundled resources are written.
+	dir  string
+	post string
+	// bundled are the names of the resources copied into the bundle.
+	bundled map[string]bool
+}
+
+// hugoResource is an entry of a page's resources.
+type hugoResource struct {
+	Src    string         `json:"src" yaml:"src"`
+	Name   string         `json:"name" yaml:"name"`
+	Title  string         `json:"title,omitempty" yaml:"title"`
+	Params map[string]any `json:"params,omitempty" yaml:"params"`
+}
+
+func newHugoPage(args []string) (*hugoPage, error) {
+	page := &hugoPage{
+		Title:   hugoTitle,
+		Tags:    []string{},
+		dir:     hugoOutput,
+		post:    lo.KebabCase(strings.ToLower(hugoTitle)) + ".md",
+		bundled: map[string]bool{},
+	}
+	if hugoBundle {
+		page.dir = filepath.Join(hugoOutput, lo.KebabCase(strings.ToLower(hugoTitle)))
+		page.post = "index.md"
+	}
+	err := os.MkdirAll(page.dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	names, err := listImages(args)
+	if err != nil {
+		return nil, err
+	}
+	imgs, err := decodeManyMuchMeta(names)
+	if err != nil {
+		return nil, err
+	}
+	page.Images, err = imgMetas(imgs)
+	if err != nil {
+		return nil, err
+	}
+	for k, i := range imgs {
+		r, err := page.addResource(i, page.Images[k])
+		if err != nil {
+			return nil, err
+		}
+		page.Resources = append(page.Resources, r)
+		page.Tags = append(page.Tags, page.Images[k].Subject...)
+		t := i.CaptureTime()
+		if !t.IsZero() && (page.Date.IsZero() || t.Before(page.Date)) {
+			page.Date = t
+		}
+	}
+	page.Tags = lo.Uniq(page.Tags)
+	return page, nil
+}
+
+// addResource bundles the image if needed and describes it as a resource,
+// generating its srcset if asked. Paths are relative to the post.
+func (page *hugoPage) addResource(i *img.Img, meta imgMeta) (hugoResource, error) {
+	src, err := page.relPath(i.Path())
+	if err != nil {
+		return hugoResource{}, err
+	}
+	if hugoBundle {
+		base := filepath.Base(i.Path())
+		ext := filepath.Ext(base)
+		base = strings.TrimSuffix(base, ext)
+		if hugoResourceFmt == "" {
+			src = page.bundleName(base, ext)
+			err = copyFile(i.Path(), filepath.Join(page.dir, src))
+		} else {
+			var f img.Format
+			f, err = img.FormatFromExtension(hugoResourceFmt)
+			if err != nil {
+				return hugoResource{}, err
+			}
+			src = page.bundleName(base, f.String())
+			err = i.SaveAs(filepath.Join(page.dir, src))
+		}
+		if err != nil {
+			return hugoResource{}, err
+		}
+	}
+	r := hugoResource{
+		Src:  filepath.ToSlash(src),
+		Name: strings.TrimSuffix(filepath.Base(src), filepath.Ext(src)),
+		Params: map[string]any{
+			"description": meta.Description,
+			"creator":     meta.Creator,
+			"rights":      meta.Rights,
+			"tags":        meta.Subject,
+			"width":       meta.Width,
+			"height":      meta.Height,
+		},
+	}
+	if len(meta.Title) > 0 {
+		r.Title = meta.Title[0]
+	}
+	if t := i.CaptureTime(); !t.IsZero() {
+		r.Params["date"] = t
+	}
+	if len(hugoWidths) > 0 {
+		set, err := page.srcset(i, src)
+		if err != nil {
+			return r, err
+		}
+		set.Alt = r.Title
+		if !hugoBundle {
+			for k, v := range set.Images {
+				if strings.HasPrefix(v.Path, "data:") {
+					continue
+				}
+				set.Images[k].Path, err = page.relPath(v.Path)
+				if err != nil {
+					return r, err
+				}
+				set.Images[k].Path = filepath.ToSlash(set.Images[k].Path)
+			}
+		}
+		r.Params["srcset"] = set.Images
+		r.Params["html"] = set.HTML()
+	}
+	return r, nil
+}
+
+// bundleName returns a file name for a resource whose name no other
+// resource of the bundle has, numbering images that share a name. Their
+// srcset variants are named after it too.
+func (page *hugoPage) bundleName(base, ext string) string {
+	name := base
+	for n := 2; page.bundled[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	page.bundled[name] = true
+	return name + ext
+}
+
+// relPath returns name relative to the post.
+func (page *hugoPage) relPath(name string) (string, error) {
+	dir, err := filepath.Abs(page.dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(dir, abs)
+}
+
+// srcset resizes the image next to src, which is relative to the bundle
+// when there is one.
+func (page *hugoPage) srcset(i *img.Img, src string) (*img.Srcset, error) {
+	formats := make([]img.Format, len(hugoFormats))
+	for k, name := range hugoFormats {
+		f, err := img.FormatFromExtension(name)
+		if err != nil {
+			return nil, err
+		}
+		formats[k] = f
+	}
+	name := strings.TrimSuffix(src, filepath.Ext(src))
+	if hugoBundle {
+		return img.GenerateSrcsetFS(img.DirFS(page.dir), name, i, hugoWidths, formats)
+	}
+	return img.GenerateSrcset(i, hugoWidths, formats)
+}
+
+// frontMatter renders the page's front matter with its delimiters.
+func (page *hugoPage) frontMatter() (string, error) {
+	m := map[string]any{
+		"title":     page.Title,
+		"tags":      page.Tags,
+		"resources": page.Resources,
+		"params": map[string]any{
+			"images": page.Images,
+		},
+	}
+	plain, err := plainValue(m)
+	if err != nil {
+		return "", err
+	}
+	m = plain.(map[string]any)
+	if !page.Date.IsZero() {
+		m["date"] = page.Date
+	}
+	var b bytes.Buffer
+	switch hugoFrontMatter {
+	case "yaml":
+		b.WriteString("---\n")
+		err = encodeYAML(&b, m)
+		b.WriteString("---")
+	case "toml":
+		var t *toml.Tree
+		t, err = toml.TreeFromMap(m)
+		if err == nil {
+			b.WriteString("+++\n")
+			err = toml.NewEncoder(&b).Encode(t)
+			b.WriteString("+++")
+		}
+	case "json":
+		err = encodeJSON(&b, m)
+		b.Truncate(len(bytes.TrimRight(b.Bytes(), "\n")))
+	default:
+		err = fmt.Errorf("--front-matter must be yaml, toml or json, not %q", hugoFrontMatter)
+	}
+	return b.String(), err
+}
+
+func (page *hugoPage) write() error {
+	var err error
+	page.FrontMatter, err = page.frontMatter()
+	if err != nil {
+		return err
+	}
+	tmpl := template.New("post")
+	if hugoTemplate == "" {
+		tmpl, err = tmpl.Parse("{{.FrontMatter}}\n")
+	} else {
+		tmpl, err = tmpl.ParseFiles(hugoTemplate)
+		if err == nil {
+			tmpl = tmpl.Lookup(filepath.Base(hugoTemplate))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	w, err := os.Create(filepath.Join(page.dir, page.post))
+	if err != nil {
+		return err
+	}
+	err = tmpl.Execute(w, page)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
 }
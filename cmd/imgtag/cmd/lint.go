@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "check image tags against a vocabulary",
+	Run: func(cmd *cobra.Command, args []string) {
+		if vocab == nil {
+			log.Fatal("lint needs a vocabulary, set one with --vocab")
+		}
+		names, err := metaImages(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		imgs, err := decodeManyMuchMeta(names)
+		if err != nil {
+			log.Fatal(err)
+		}
+		problems := 0
+		for i, im := range imgs {
+			msgs, err := lintTags(im.Tags())
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, msg := range msgs {
+				fmt.Printf("%s: %s\n", names[i], msg)
+				problems++
+			}
+		}
+		if problems > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintTags reports tags that aren't in the vocabulary, or whose path isn't
+// the canonical one because it uses an alias or is missing parents.
+func lintTags(tags *img.Tags) ([]string, error) {
+	var msgs []string
+	for _, p := range tags.Paths() {
+		path := strings.Join(p, img.Separator)
+		canon, ok, err := vocab.Lookup(p[len(p)-1])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !ok:
+			msgs = append(msgs, fmt.Sprintf("unknown tag %q", path))
+		case !slices.Equal(p, canon):
+			msgs = append(msgs, fmt.Sprintf("%q should be %q", path, strings.Join(canon, img.Separator)))
+		}
+	}
+	return msgs, nil
+}
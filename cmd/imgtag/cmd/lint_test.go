@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/ohzqq/img"
+)
+
+func TestLintTags(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() { vocab = nil })
+	var err error
+	vocab, err = img.LoadVocabulary(strings.NewReader(`
+keywords:
+  - name: Places
+    children:
+      - name: New York City
+        aliases: [NYC]
+  - name: People
+`))
+	c.Assert(err, qt.IsNil)
+
+	tags := img.FromPaths([]string{
+		"Places > New York City",
+		"People",
+		"Places > NYC",
+		"New York City",
+		"Travel > Summer",
+	}, "")
+	msgs, err := lintTags(tags)
+	c.Assert(err, qt.IsNil)
+	c.Assert(msgs, qt.DeepEquals, []string{
+		`"Places > NYC" should be "Places > New York City"`,
+		`"New York City" should be "Places > New York City"`,
+		`unknown tag "Travel > Summer"`,
+	})
+
+	vocab = &img.Vocabulary{Keywords: []*img.Keyword{{Name: "A"}, {Name: "a"}}}
+	_, err = lintTags(img.FromPaths([]string{"A"}, ""))
+	c.Assert(err, qt.IsNotNil)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var mergeStrategy string
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge [flags] src dst...",
+	Short: "merge the metadata of one image into others",
+	Long: `Merge copies the title, creator, description, rights and tags of src
+into each dst. With prefer-src the values of src win, with prefer-dst only
+missing fields are filled in and with union the creators and tags of both
+are kept.`,
+	Args: minImages(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var strategy img.MergeStrategy
+		switch mergeStrategy {
+		case "prefer-src":
+			strategy = img.PreferSrc
+		case "prefer-dst":
+			strategy = img.PreferDst
+		case "union":
+			strategy = img.Union
+		default:
+			log.Fatalf("unknown strategy %q, use prefer-src, prefer-dst or union", mergeStrategy)
+		}
+		src, err := decodeMeta(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = editImages(args[1:], func(dst *img.Img) error {
+			img.MergeMeta(dst, src, strategy)
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().StringVar(&mergeStrategy, "strategy", "union", "prefer-src, prefer-dst or union")
+	addEditFlags(mergeCmd)
+}
@@ -1,15 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
+	"fmt"
+	"image/color"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/evanoberholster/imagemeta/xmp"
-	"github.com/goccy/go-yaml"
 	"github.com/ohzqq/img"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +20,13 @@ var (
 	EXT         string
 	outputFile  string
 	batchOutput string
+	vocabFile   string
+	vocab       *img.Vocabulary
+	sidecarPref string
+	idScheme    string
+	idRoot      string
+	formatName  string
+	listSep     string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,13 +34,23 @@ var rootCmd = &cobra.Command{
 	Use:   "imgtag",
 	Short: "get some image meta",
 	Long:  ``,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := parseFields(cmd.Root().PersistentFlags())
+		if err != nil {
+			return err
+		}
+		if vocabFile == "" {
+			return nil
+		}
+		vocab, err = img.OpenVocabulary(vocabFile)
+		return err
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		metas, err := metaSlice(args)
 		if err != nil {
 			log.Fatal(err)
 		}
-		out := outputFile + getExt()
-		err = saveMeta(out, metas)
+		err = saveMeta(outputName(cmd), metas)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -54,36 +71,52 @@ func init() {
 	rootCmd.PersistentFlags().BoolP("title", "t", false, "get title")
 	rootCmd.PersistentFlags().BoolP("description", "d", false, "get description")
 	rootCmd.PersistentFlags().BoolP("subject", "s", false, "get subject")
+	rootCmd.PersistentFlags().StringSliceVar(&fieldList, "fields", nil, "only get these fields: "+strings.Join(outputFields, ", "))
 	rootCmd.PersistentFlags().StringVarP(&batchOutput, "batch", "b", "", "output the metadata as a batch/slice/array")
 	rootCmd.PersistentFlags().BoolVarP(&YAML, "yaml", "y", true, "marshal to yaml")
 	rootCmd.PersistentFlags().BoolVarP(&JSON, "json", "j", false, "marshal to json")
 	rootCmd.PersistentFlags().StringP("write", "w", ".yaml", "output meta to file")
+	rootCmd.PersistentFlags().MarkDeprecated("write", "use --format")
+	rootCmd.PersistentFlags().StringVarP(&formatName, "format", "f", "", "format of the meta: "+metaFormatNames()+", or by the output's extension")
+	rootCmd.PersistentFlags().StringVar(&listSep, "list-sep", "; ", "separator of list values in csv")
 	rootCmd.PersistentFlags().StringVarP(&EXT, "ext", "e", "", "extension for meta files")
 	rootCmd.PersistentFlags().StringVarP(&outputFile, "output", "o", "images.yaml", "file output name")
+	rootCmd.PersistentFlags().StringVar(&vocabFile, "vocab", "", "normalize tags with a yaml vocabulary")
+	rootCmd.PersistentFlags().StringVar(&idScheme, "id", "path", "identify images by path, relative, sha256, pixels or uuid, which the editing commands save to images without one")
+	rootCmd.PersistentFlags().StringVar(&idRoot, "id-root", "", "directory that relative ids start from")
+	rootCmd.PersistentFlags().StringVar(&sidecarPref, "sidecar", "", "merge .xmp sidecars, preferring the \"embedded\" or \"sidecar\" values")
 }
 
 func writeMeta(args []string) error {
-	metas, err := decodeManyMuchMeta(args)
+	names, err := metaImages(args)
+	if err != nil {
+		return err
+	}
+	metas, err := decodeManyMuchMeta(names)
+	if err != nil {
+		return err
+	}
+	f, err := outputFormat("")
 	if err != nil {
 		return err
 	}
-	w := os.Stdout
 	for i, meta := range metas {
-		dir, name := filepath.Split(args[i])
-		name = strings.TrimSuffix(name, filepath.Ext(args[i]))
-		ext := ".yaml"
-		if JSON {
-			ext = ".json"
-		}
+		dir, name := filepath.Split(names[i])
+		name = strings.TrimSuffix(name, filepath.Ext(names[i]))
+		ext := f.exts[0]
 		if EXT != "" {
 			ext = EXT
 		}
-		w, err = os.Create(filepath.Join(dir, name) + ext)
+		w, err := os.Create(filepath.Join(dir, name) + ext)
 		if err != nil {
 			return err
 		}
 		defer w.Close()
-		err = encodeMeta(w, meta.DublinCore())
+		m, err := newImgMeta(meta)
+		if err != nil {
+			return err
+		}
+		err = f.encode(w, m)
 		if err != nil {
 			return err
 		}
@@ -96,48 +129,16 @@ func writeMetaBatch(args []string) error {
 	if err != nil {
 		return err
 	}
-	ext := filepath.Ext(batchOutput)
-	switch ext {
-	case "":
-		batchOutput = batchOutput + getExt()
-	case ".yaml", ".yml":
-		YAML = true
-	case ".json":
-		JSON = true
-	}
-	out, err := os.Create(batchOutput)
-	if err != nil {
-		return err
-	}
-	return encodeMeta(out, all)
+	return saveMeta(batchOutput, all)
 }
 
+// encodeMeta writes meta to w in the format chosen by the flags.
 func encodeMeta(w io.Writer, meta any) error {
-	if JSON {
-		err := encodeJSON(w, meta)
-		if err != nil {
-			return err
-		}
-	} else if YAML {
-		err := encodeYAML(w, meta)
-		if err != nil {
-			return err
-		}
-		w.Write([]byte("---"))
+	f, err := outputFormat("")
+	if err != nil {
+		return err
 	}
-	return nil
-}
-
-func encodeJSON(w io.Writer, meta any) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(meta)
-}
-
-func encodeYAML(w io.Writer, meta any) error {
-	enc := yaml.NewEncoder(w, yaml.Indent(2), yaml.AutoInt(), yaml.OmitEmpty())
-	defer enc.Close()
-	return enc.Encode(meta)
+	return f.encode(w, meta)
 }
 
 func decodeManyMuchMeta(args []string) ([]*img.Img, error) {
@@ -157,61 +158,240 @@ func decodeMeta(name string) (*img.Img, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = i.ReadMeta()
+	var opts []img.DecodeOption
+	switch sidecarPref {
+	case "":
+	case "embedded":
+		opts = append(opts, img.Sidecar(img.PreferEmbedded))
+	case "sidecar":
+		opts = append(opts, img.Sidecar(img.PreferSidecar))
+	default:
+		return nil, fmt.Errorf("--sidecar must be embedded or sidecar, not %q", sidecarPref)
+	}
+	scheme, ok := idSchemes[idScheme]
+	if !ok {
+		return nil, fmt.Errorf("--id must be path, relative, sha256, pixels or uuid, not %q", idScheme)
+	}
+	opts = append(opts, img.Identifier(scheme))
+	if idRoot != "" {
+		if scheme != img.IDRelative {
+			return nil, fmt.Errorf("--id-root only applies to --id relative, not %q", idScheme)
+		}
+		opts = append(opts, img.IDRoot(idRoot))
+	}
+	err = i.ReadMeta(opts...)
 	if err != nil {
 		return nil, err
 	}
 	return i, nil
 }
 
-func getExt() string {
-	ext := ".yaml"
-	if JSON {
-		ext = ".json"
+var idSchemes = map[string]img.IDScheme{
+	"path":     img.IDPath,
+	"relative": img.IDRelative,
+	"sha256":   img.IDFileHash,
+	"pixels":   img.IDPixelHash,
+	"uuid":     img.IDDocument,
+}
+
+// outputName is --output, if it was given, so its extension picks the
+// format. The default name gets the extension of the format from the other
+// flags.
+func outputName(cmd *cobra.Command) string {
+	if cmd.Flags().Changed("output") {
+		return outputFile
+	}
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile))
+}
+
+// imgMeta is the metadata written by the commands, with stable lowercase
+// keys. Only the fields chosen with --fields or the field flags are set,
+// besides the identifier and, when the identifier isn't the path, the file.
+// Srcset and HTML are set by hugo --srcset.
+type imgMeta struct {
+	Identifier  string            `json:"identifier" yaml:"identifier"`
+	File        string            `json:"file,omitempty" yaml:"file,omitempty"`
+	Title       []string          `json:"title,omitempty" yaml:"title,omitempty"`
+	Creator     []string          `json:"creator,omitempty" yaml:"creator,omitempty"`
+	Description []string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Rights      []string          `json:"rights,omitempty" yaml:"rights,omitempty"`
+	Subject     []string          `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Date        *time.Time        `json:"date,omitempty" yaml:"date,omitempty"`
+	Format      string            `json:"format,omitempty" yaml:"format,omitempty"`
+	Width       int               `json:"width,omitempty" yaml:"width,omitempty"`
+	Height      int               `json:"height,omitempty" yaml:"height,omitempty"`
+	ColorModel  string            `json:"colorModel,omitempty" yaml:"colorModel,omitempty"`
+	GPS         *gpsMeta          `json:"gps,omitempty" yaml:"gps,omitempty"`
+	Srcset      []img.SrcsetImage `json:"srcset,omitempty" yaml:"srcset,omitempty"`
+	HTML        string            `json:"html,omitempty" yaml:"html,omitempty"`
+	img         *img.Img
+}
+
+// gpsMeta is a location in decimal degrees.
+type gpsMeta struct {
+	Latitude  float64 `json:"latitude" yaml:"latitude"`
+	Longitude float64 `json:"longitude" yaml:"longitude"`
+}
+
+// csvColumns are the fields shown by the flags, whether or not the image has
+// them, in the order they are encoded. The file is only shown with an --id
+// other than path.
+func (m imgMeta) csvColumns() []string {
+	cols := []string{"identifier"}
+	if idScheme != "path" {
+		cols = append(cols, "file")
+	}
+	for _, f := range outputFields {
+		switch {
+		case !showField(f):
+		case f == "gps":
+			cols = append(cols, "gps.latitude", "gps.longitude")
+		default:
+			cols = append(cols, f)
+		}
+	}
+	return cols
+}
+
+// newImgMeta describes the image, only reading its header if the
+// dimensions or color model are shown.
+func newImgMeta(i *img.Img) (imgMeta, error) {
+	dc := i.DublinCore()
+	m := imgMeta{Identifier: dc.Identifier, img: i}
+	if m.Identifier != i.Path() {
+		m.File = i.Path()
+	}
+	for _, f := range []struct {
+		name string
+		dst  *[]string
+		src  []string
+	}{
+		{"title", &m.Title, dc.Title},
+		{"creator", &m.Creator, dc.Creator},
+		{"description", &m.Description, dc.Description},
+		{"rights", &m.Rights, dc.Rights},
+		{"subject", &m.Subject, dc.Subject},
+	} {
+		if showField(f.name) {
+			*f.dst = f.src
+		}
+	}
+	if vocab != nil && len(m.Subject) > 0 {
+		tags, err := img.UnmarshalField(img.Subject, m.Subject)
+		if err != nil {
+			return m, err
+		}
+		_, err = tags.Normalize(vocab)
+		if err != nil {
+			return m, err
+		}
+		m.Subject = tags.StringSlice()
+	}
+	if t := i.CaptureTime(); showField("date") && !t.IsZero() {
+		m.Date = &t
+	}
+	if showField("format") {
+		m.Format = i.Fmt.MimeType()
+	}
+	if lat, long, ok := i.GPS(); showField("gps") && ok {
+		m.GPS = &gpsMeta{Latitude: lat, Longitude: long}
+	}
+	if !showField("width") && !showField("height") && !showField("colorModel") {
+		return m, nil
+	}
+	cfg, err := i.Config()
+	if err != nil {
+		return m, err
+	}
+	if showField("width") {
+		m.Width = cfg.Width
+	}
+	if showField("height") {
+		m.Height = cfg.Height
+	}
+	if showField("colorModel") {
+		m.ColorModel = colorModelName(cfg.ColorModel)
 	}
-	if EXT != "" {
-		ext = EXT
+	return m, nil
+}
+
+func colorModelName(m color.Model) string {
+	switch m {
+	case color.RGBAModel:
+		return "rgba"
+	case color.RGBA64Model:
+		return "rgba64"
+	case color.NRGBAModel:
+		return "nrgba"
+	case color.NRGBA64Model:
+		return "nrgba64"
+	case color.AlphaModel:
+		return "alpha"
+	case color.Alpha16Model:
+		return "alpha16"
+	case color.GrayModel:
+		return "gray"
+	case color.Gray16Model:
+		return "gray16"
+	case color.YCbCrModel:
+		return "ycbcr"
+	case color.NYCbCrAModel:
+		return "nycbcra"
+	case color.CMYKModel:
+		return "cmyk"
+	}
+	if _, ok := m.(color.Palette); ok {
+		return "paletted"
 	}
-	return ext
+	return ""
 }
 
-func metaSlice(args []string) ([]xmp.DublinCore, error) {
-	metas, err := decodeManyMuchMeta(args)
+func metaSlice(args []string) ([]imgMeta, error) {
+	names, err := metaImages(args)
+	if err != nil {
+		return nil, err
+	}
+	metas, err := decodeManyMuchMeta(names)
 	if err != nil {
 		return nil, err
 	}
-	all := make([]xmp.DublinCore, len(args))
+	return imgMetas(metas)
+}
+
+// imgMetas describes the images, titling those without one after their
+// file.
+func imgMetas(metas []*img.Img) ([]imgMeta, error) {
+	var err error
+	all := make([]imgMeta, len(metas))
 	for i, meta := range metas {
-		all[i] = meta.DublinCore()
-		if len(all[i].Title) == 0 {
+		all[i], err = newImgMeta(meta)
+		if err != nil {
+			return nil, err
+		}
+		if len(all[i].Title) == 0 && showField("title") {
 			all[i].Title = []string{
-				strings.TrimSuffix(filepath.Base(all[i].Identifier), filepath.Ext(all[i].Identifier)),
+				strings.TrimSuffix(filepath.Base(meta.Path()), filepath.Ext(meta.Path())),
 			}
 		}
 	}
 	return all, nil
 }
 
+// saveMeta writes m to name in the format of its extension, unless the
+// flags choose one, adding the format's extension if it's missing.
 func saveMeta(name string, m any) error {
-	w, err := os.Create(name)
+	f, err := outputFormat(name)
 	if err != nil {
 		return err
 	}
-	switch {
-	case YAML:
-		enc := yaml.NewEncoder(w, yaml.Indent(2), yaml.AutoInt(), yaml.OmitEmpty())
-		err = enc.Encode(m)
-		if err != nil {
-			return err
-		}
-		w.Write([]byte("---"))
-	case JSON:
-		enc := json.NewEncoder(w)
-		enc.SetIndent("", "  ")
-		err = enc.Encode(m)
-		if err != nil {
-			return err
-		}
+	w, err := os.Create(f.withExt(name))
+	if err != nil {
+		return err
 	}
-	return nil
+	err = f.encode(w, m)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
 }
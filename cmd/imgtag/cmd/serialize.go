@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml"
+)
+
+// metaEncoder writes metadata, a single value or a slice of them, to w.
+type metaEncoder func(w io.Writer, meta any) error
+
+// metaFormat is a serialization that metadata can be written in.
+type metaFormat struct {
+	name   string
+	exts   []string
+	encode metaEncoder
+}
+
+// metaFormats are the serializations selected by --format or the extension
+// of an output file. The first extension names new files.
+var metaFormats = []metaFormat{
+	{name: "yaml", exts: []string{".yaml", ".yml"}, encode: encodeYAMLDoc},
+	{name: "json", exts: []string{".json"}, encode: encodeJSON},
+	{name: "toml", exts: []string{".toml"}, encode: encodeTOML},
+	{name: "csv", exts: []string{".csv"}, encode: encodeCSV},
+	{name: "ndjson", exts: []string{".ndjson", ".jsonl"}, encode: encodeNDJSON},
+	{name: "xmp", exts: []string{".xmp"}, encode: encodeXMP},
+}
+
+// metaFormatNames lists the registered formats for help and errors.
+func metaFormatNames() string {
+	var names []string
+	for _, f := range metaFormats {
+		names = append(names, f.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// outputFormat picks the serialization from --format, then the extension of
+// name, then --json, defaulting to yaml.
+func outputFormat(name string) (metaFormat, error) {
+	byName := func(name string) (metaFormat, bool) {
+		for _, f := range metaFormats {
+			if f.name == name {
+				return f, true
+			}
+		}
+		return metaFormat{}, false
+	}
+	if formatName != "" {
+		f, ok := byName(formatName)
+		if !ok {
+			return f, fmt.Errorf("unknown format %q, use %s", formatName, metaFormatNames())
+		}
+		return f, nil
+	}
+	if ext := strings.ToLower(filepath.Ext(name)); ext != "" {
+		for _, f := range metaFormats {
+			if slices.Contains(f.exts, ext) {
+				return f, nil
+			}
+		}
+	}
+	if JSON {
+		f, _ := byName("json")
+		return f, nil
+	}
+	f, _ := byName("yaml")
+	return f, nil
+}
+
+// withExt adds the format's extension to name unless it already has one of
+// them.
+func (f metaFormat) withExt(name string) string {
+	if slices.Contains(f.exts, strings.ToLower(filepath.Ext(name))) {
+		return name
+	}
+	return name + f.exts[0]
+}
+
+func encodeJSON(w io.Writer, meta any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(meta)
+}
+
+func encodeYAML(w io.Writer, meta any) error {
+	enc := yaml.NewEncoder(w, yaml.Indent(2), yaml.AutoInt(), yaml.OmitEmpty())
+	defer enc.Close()
+	return enc.Encode(meta)
+}
+
+// encodeYAMLDoc ends the yaml with a document marker.
+func encodeYAMLDoc(w io.Writer, meta any) error {
+	err := encodeYAML(w, meta)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte("---"))
+	return err
+}
+
+// encodeTOML writes a slice as an array of tables named images, since a
+// TOML document must be a table.
+func encodeTOML(w io.Writer, meta any) error {
+	v, err := plainValue(meta)
+	if err != nil {
+		return err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		m = map[string]any{"images": v}
+	}
+	t, err := toml.TreeFromMap(m)
+	if err != nil {
+		return err
+	}
+	return toml.NewEncoder(w).Encode(t)
+}
+
+// encodeNDJSON writes each element of a slice as a line of json.
+func encodeNDJSON(w io.Writer, meta any) error {
+	enc := json.NewEncoder(w)
+	for _, e := range elements(meta) {
+		err := enc.Encode(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeXMP writes an XMP packet for each image.
+func encodeXMP(w io.Writer, meta any) error {
+	for _, e := range elements(meta) {
+		m, ok := e.(imgMeta)
+		if !ok || m.img == nil {
+			return fmt.Errorf("xmp can only be written for images, not %T", e)
+		}
+		if vocab != nil {
+			tags := m.img.Tags()
+			_, err := tags.Normalize(vocab)
+			if err != nil {
+				return err
+			}
+			m.img.SetTags(tags)
+		}
+		err := m.img.EncodeXMP(w)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte("\n"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elements returns the elements of a slice, or meta itself.
+func elements(meta any) []any {
+	rv := reflect.ValueOf(meta)
+	if rv.Kind() != reflect.Slice {
+		return []any{meta}
+	}
+	out := make([]any, rv.Len())
+	for k := range out {
+		out[k] = rv.Index(k).Interface()
+	}
+	return out
+}
+
+// csvColumner is implemented by values that omit their empty fields, to
+// name the columns they always have.
+type csvColumner interface {
+	csvColumns() []string
+}
+
+// encodeCSV writes a row for each element of a slice. Nested fields become
+// columns named parent.child, and lists of values are joined with
+// --list-sep. The rows of a slice of slices, such as the groups of dupes,
+// are numbered in a group column.
+func encodeCSV(w io.Writer, meta any) error {
+	var (
+		header []string
+		rows   []map[string]string
+	)
+	add := func(row map[string]string, v any) error {
+		if c, ok := v.(csvColumner); ok {
+			for _, col := range c.csvColumns() {
+				if !slices.Contains(header, col) {
+					header = append(header, col)
+				}
+			}
+		}
+		m, err := orderedValue(v)
+		if err != nil {
+			return err
+		}
+		for _, item := range m {
+			flattenCSV(row, &header, fmt.Sprint(item.Key), item.Value)
+		}
+		rows = append(rows, row)
+		return nil
+	}
+	for k, e := range elements(meta) {
+		if reflect.ValueOf(e).Kind() != reflect.Slice {
+			err := add(map[string]string{}, e)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if k == 0 {
+			header = append(header, "group")
+		}
+		for _, ge := range elements(e) {
+			err := add(map[string]string{"group": strconv.Itoa(k + 1)}, ge)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw := csv.NewWriter(w)
+	err := cw.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		rec := make([]string, len(header))
+		for k, col := range header {
+			rec[k] = row[col]
+		}
+		err = cw.Write(rec)
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func flattenCSV(row map[string]string, header *[]string, key string, v any) {
+	set := func(s string) {
+		if !slices.Contains(*header, key) {
+			*header = append(*header, key)
+		}
+		row[key] = s
+	}
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			flattenCSV(row, header, key+"."+fmt.Sprint(item.Key), item.Value)
+		}
+	case []any:
+		var (
+			vals   []string
+			nested bool
+		)
+		for k, e := range v {
+			switch e.(type) {
+			case yaml.MapSlice, []any:
+				nested = true
+				flattenCSV(row, header, key+"."+strconv.Itoa(k), e)
+			default:
+				vals = append(vals, fmt.Sprint(e))
+			}
+		}
+		if !nested {
+			set(strings.Join(vals, listSep))
+		}
+	case nil:
+		set("")
+	default:
+		set(fmt.Sprint(v))
+	}
+}
+
+// orderedValue converts v to a yaml.MapSlice, keeping the order of its
+// fields.
+func orderedValue(v any) (yaml.MapSlice, error) {
+	var m yaml.MapSlice
+	b, err := yaml.MarshalWithOptions(v, yaml.AutoInt())
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.UnmarshalWithOptions(b, &m, yaml.UseOrderedMap())
+	return m, err
+}
+
+// plainValue converts v to maps, slices and scalars through its yaml form,
+// dropping empty values, so every format encodes it with the same keys.
+func plainValue(v any) (any, error) {
+	var b bytes.Buffer
+	err := encodeYAML(&b, v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = yaml.Unmarshal(b.Bytes(), &out)
+	if err != nil {
+		return nil, err
+	}
+	return prune(out), nil
+}
+
+func prune(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			e = prune(e)
+			if e == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = e
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []any:
+		var out []any
+		for _, e := range v {
+			if e = prune(e); e != nil {
+				out = append(out, e)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return v
+}
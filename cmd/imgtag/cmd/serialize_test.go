@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/ohzqq/img"
+	"github.com/pelletier/go-toml"
+	"github.com/spf13/cobra"
+)
+
+func TestEncodeCSVColumns(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() {
+		shownFields = map[string]bool{}
+		idScheme = "path"
+	})
+	tests := []struct {
+		fields []string
+		id     string
+		metas  []imgMeta
+		want   string
+	}{
+		{
+			id: "path",
+			metas: []imgMeta{
+				{Identifier: "a.jpg", Width: 4},
+				{Identifier: "b.jpg", Title: []string{"B"}, Subject: []string{"x", "y"}},
+			},
+			want: "identifier,title,creator,description,rights,subject,date,format,width,height,colorModel,gps.latitude,gps.longitude\n" +
+				"a.jpg,,,,,,,,4,,,,\n" +
+				"b.jpg,B,,,,x; y,,,,,,,\n",
+		},
+		{
+			fields: []string{"title"},
+			id:     "path",
+			metas:  []imgMeta{{Identifier: "a.jpg"}, {Identifier: "b.jpg", Title: []string{"B"}}},
+			want:   "identifier,title\na.jpg,\nb.jpg,B\n",
+		},
+		{
+			fields: []string{"gps", "title"},
+			id:     "sha256",
+			metas:  []imgMeta{{Identifier: "a.jpg"}, {Identifier: "b.jpg", Title: []string{"B"}}},
+			want:   "identifier,file,title,gps.latitude,gps.longitude\na.jpg,,,,\nb.jpg,,B,,\n",
+		},
+	}
+	for _, test := range tests {
+		shownFields = map[string]bool{}
+		for _, f := range test.fields {
+			shownFields[f] = true
+		}
+		idScheme = test.id
+		var buf bytes.Buffer
+		c.Assert(encodeCSV(&buf, test.metas), qt.IsNil)
+		c.Assert(buf.String(), qt.Equals, test.want, qt.Commentf("%v", test.fields))
+	}
+}
+
+// testMeta describes a 4x2 jpeg named name, edited by edit.
+func testMeta(c *qt.C, name string, edit func(*img.Img)) imgMeta {
+	name = filepath.Join(c.TempDir(), name)
+	f, err := os.Create(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jpeg.Encode(f, image.NewGray(image.Rect(0, 0, 4, 2)), nil), qt.IsNil)
+	c.Assert(f.Close(), qt.IsNil)
+	i, err := img.New(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.ReadMeta(), qt.IsNil)
+	if edit != nil {
+		edit(i)
+	}
+	m, err := newImgMeta(i)
+	c.Assert(err, qt.IsNil)
+	return m
+}
+
+func TestMetaFormats(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() { listSep = "; " })
+	metas := []imgMeta{
+		testMeta(c, "a.jpg", func(i *img.Img) {
+			i.SetTitle("A")
+			tags, err := img.UnmarshalField(img.Subject, []string{"x", "y, z"})
+			c.Assert(err, qt.IsNil)
+			i.SetTags(tags)
+		}),
+		testMeta(c, "b.jpg", nil),
+	}
+	a, b := metas[0].img.Path(), metas[1].img.Path()
+
+	c.Run("toml", func(c *qt.C) {
+		var buf bytes.Buffer
+		c.Assert(encodeTOML(&buf, metas[0]), qt.IsNil)
+		tree, err := toml.Load(buf.String())
+		c.Assert(err, qt.IsNil)
+		c.Assert(tree.Get("identifier"), qt.Equals, a)
+		c.Assert(tree.Get("title"), qt.DeepEquals, []any{"A"})
+		c.Assert(tree.Get("subject"), qt.DeepEquals, []any{"x", "y, z"})
+		c.Assert(tree.Get("width"), qt.Equals, int64(4))
+
+		// a slice is an array of tables
+		buf.Reset()
+		c.Assert(encodeTOML(&buf, metas), qt.IsNil)
+		tree, err = toml.Load(buf.String())
+		c.Assert(err, qt.IsNil)
+		images, ok := tree.Get("images").([]*toml.Tree)
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(images, qt.HasLen, 2)
+		c.Assert(images[1].Get("identifier"), qt.Equals, b)
+		c.Assert(images[1].Get("height"), qt.Equals, int64(2))
+	})
+
+	c.Run("ndjson", func(c *qt.C) {
+		for _, test := range []struct {
+			meta any
+			want []string
+		}{
+			{metas, []string{a, b}},
+			{metas[1], []string{b}},
+		} {
+			var buf bytes.Buffer
+			c.Assert(encodeNDJSON(&buf, test.meta), qt.IsNil)
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			c.Assert(lines, qt.HasLen, len(test.want))
+			for k, line := range lines {
+				var m map[string]any
+				c.Assert(json.Unmarshal([]byte(line), &m), qt.IsNil)
+				c.Assert(line, qt.Contains, strconv.Quote(test.want[k]))
+			}
+		}
+	})
+
+	c.Run("csv", func(c *qt.C) {
+		for _, sep := range []string{"; ", "|"} {
+			listSep = sep
+			var buf bytes.Buffer
+			c.Assert(encodeCSV(&buf, metas), qt.IsNil)
+			rows := csvRows(c, buf.String())
+			c.Assert(rows, qt.HasLen, 2)
+			c.Assert(rows[0]["identifier"], qt.Equals, a)
+			c.Assert(rows[0]["title"], qt.Equals, "A")
+			c.Assert(rows[0]["subject"], qt.Equals, "x"+sep+"y, z")
+			c.Assert(rows[1]["identifier"], qt.Equals, b)
+			c.Assert(rows[1]["height"], qt.Equals, "2")
+		}
+	})
+
+	c.Run("csv groups", func(c *qt.C) {
+		groups := [][]dupeImage{
+			{{Identifier: "a.jpg", Size: 10}, {Identifier: "c.jpg", Size: 5, Distance: 3}},
+			{{Identifier: "b.jpg", Size: 1}},
+		}
+		var buf bytes.Buffer
+		c.Assert(encodeCSV(&buf, groups), qt.IsNil)
+		c.Assert(strings.HasPrefix(buf.String(), "group,identifier,"), qt.IsTrue)
+		rows := csvRows(c, buf.String())
+		var got [][]string
+		for _, row := range rows {
+			got = append(got, []string{row["group"], row["identifier"], row["distance"]})
+		}
+		c.Assert(got, qt.DeepEquals, [][]string{{"1", "a.jpg", "0"}, {"1", "c.jpg", "3"}, {"2", "b.jpg", "0"}})
+	})
+}
+
+// csvRows reads csv into a map of each row by column.
+func csvRows(c *qt.C, s string) []map[string]string {
+	recs, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	c.Assert(err, qt.IsNil)
+	var rows []map[string]string
+	for _, rec := range recs[1:] {
+		row := map[string]string{}
+		for k, col := range recs[0] {
+			row[col] = rec[k]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestEncodeXMP(t *testing.T) {
+	c := qt.New(t)
+	metas := []imgMeta{
+		testMeta(c, "rome.jpg", func(i *img.Img) { i.SetTitle("Rome") }),
+		testMeta(c, "paris.jpg", func(i *img.Img) { i.SetTitle("Paris") }),
+	}
+	var buf bytes.Buffer
+	c.Assert(encodeXMP(&buf, metas), qt.IsNil)
+	out := buf.String()
+	c.Assert(strings.Count(out, "<x:xmpmeta"), qt.Equals, 2)
+	c.Assert(strings.Index(out, "Rome") < strings.Index(out, "Paris"), qt.IsTrue)
+
+	err := encodeXMP(&buf, [][]dupeImage{{{Identifier: "a.jpg"}}})
+	c.Assert(err, qt.ErrorMatches, `xmp can only be written for images, not \[\]cmd.dupeImage`)
+}
+
+func TestOutputFormat(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() {
+		formatName = ""
+		JSON = false
+	})
+	tests := []struct {
+		format string
+		json   bool
+		name   string
+		want   string
+		ext    string
+	}{
+		{name: "", want: "yaml", ext: "images.yaml"},
+		{name: "images", json: true, want: "json", ext: "images.json"},
+		{name: "images.YML", want: "yaml", ext: "images.YML"},
+		{name: "images.jsonl", want: "ndjson", ext: "images.jsonl"},
+		{name: "images.csv", json: true, want: "csv", ext: "images.csv"},
+		{name: "images.txt", want: "yaml", ext: "images.txt.yaml"},
+		{format: "toml", name: "images.csv", want: "toml", ext: "images.csv.toml"},
+		{format: "xmp", name: "images", want: "xmp", ext: "images.xmp"},
+	}
+	for _, test := range tests {
+		formatName, JSON = test.format, test.json
+		f, err := outputFormat(test.name)
+		c.Assert(err, qt.IsNil)
+		c.Assert(f.name, qt.Equals, test.want, qt.Commentf("%q", test.name))
+		if test.name == "" {
+			test.name = "images"
+		}
+		c.Assert(f.withExt(test.name), qt.Equals, test.ext)
+	}
+	formatName = "xml"
+	_, err := outputFormat("images.yaml")
+	c.Assert(err, qt.ErrorMatches, `unknown format "xml", use yaml, json, toml, csv, ndjson, xmp`)
+}
+
+func TestOutputName(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() { outputFile = "images.yaml" })
+	for _, test := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "images"},
+		{[]string{"-o", "out.csv"}, "out.csv"},
+		{[]string{"-o", "out"}, "out"},
+	} {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVarP(&outputFile, "output", "o", "images.yaml", "")
+		c.Assert(cmd.ParseFlags(test.args), qt.IsNil)
+		c.Assert(outputName(cmd), qt.Equals, test.want)
+	}
+}
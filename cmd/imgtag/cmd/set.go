@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setTitle       []string
+	setCreator     []string
+	setDescription []string
+)
+
+// setCmd represents the set command
+var setCmd = &cobra.Command{
+	Use:   "set [flags] images...",
+	Short: "set the title, creator or description",
+	Args:  minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		err := editImages(args, func(i *img.Img) error {
+			if flags.Changed("title") {
+				i.SetTitle(nonEmpty(setTitle)...)
+			}
+			if flags.Changed("creator") {
+				i.SetCreator(nonEmpty(setCreator)...)
+			}
+			if flags.Changed("description") {
+				i.SetDescription(nonEmpty(setDescription)...)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	// these shadow the persistent bools of the same name
+	setCmd.Flags().StringArrayVar(&setTitle, "title", nil, "set the title, empty to remove it")
+	setCmd.Flags().StringArrayVar(&setCreator, "creator", nil, "set a creator, repeat for several")
+	setCmd.Flags().StringArrayVar(&setDescription, "description", nil, "set the description, empty to remove it")
+	addEditFlags(setCmd)
+}
+
+func nonEmpty(vals []string) []string {
+	var out []string
+	for _, v := range vals {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
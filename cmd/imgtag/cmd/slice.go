@@ -16,8 +16,7 @@ var sliceCmd = &cobra.Command{
 		if err != nil {
 			log.Fatal(err)
 		}
-		out := outputFile + getExt()
-		err = saveMeta(out, metas)
+		err = saveMeta(outputName(cmd), metas)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stripPolicy string
+	stripKeep   []string
+)
+
+// stripCmd represents the strip command
+var stripCmd = &cobra.Command{
+	Use:   "strip [flags] images...",
+	Short: "remove metadata before publishing",
+	Long: `Strip removes metadata from jpeg, png, webp and tiff images without
+re-encoding them. Policies are:
+
+  all             remove everything but the orientation and color profile
+  gps-only        remove only the location
+  keep-copyright  remove everything but the creator and rights
+
+--keep lists the fields to keep instead: title, creator, rights,
+description or tags.`,
+	Args: minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := parseStripPolicy()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if editDir != "" {
+			err := os.MkdirAll(editDir, 0755)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		names, err := metaImages(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range names {
+			i, err := decodeMeta(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if editDir == "" {
+				err = i.Save(img.StripMeta(policy))
+			} else {
+				err = i.SaveAs(filepath.Join(editDir, filepath.Base(name)), img.StripMeta(policy))
+			}
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stripCmd)
+	stripCmd.Flags().StringVar(&stripPolicy, "policy", "all", "all, gps-only or keep-copyright")
+	stripCmd.Flags().StringSliceVar(&stripKeep, "keep", nil, "remove everything but these fields: title, creator, rights, description, tags")
+	stripCmd.Flags().StringVarP(&editDir, "output", "o", "", "save the stripped images to this directory instead of in place")
+}
+
+func parseStripPolicy() (img.StripPolicy, error) {
+	if len(stripKeep) > 0 {
+		var fields []img.ExifField
+		for _, name := range stripKeep {
+			f, ok := copyFieldNames[name]
+			if !ok {
+				return img.StripPolicy{}, fmt.Errorf("unknown field %q, use title, creator, rights, description or tags", name)
+			}
+			fields = append(fields, f)
+		}
+		return img.KeepFields(fields...), nil
+	}
+	switch stripPolicy {
+	case "all":
+		return img.StripAll, nil
+	case "gps-only":
+		return img.StripGPS, nil
+	case "keep-copyright":
+		return img.KeepCopyright, nil
+	}
+	return img.StripPolicy{}, fmt.Errorf("unknown policy %q, use all, gps-only or keep-copyright", stripPolicy)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "edit hierarchical tags",
+	Long: `Edit the tag tree of images. Paths are split on " > ", "|" or "/",
+so "Places > New York City" and "Places|New York City" are the same tag.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add path images...",
+	Short: "add a tag and its parents",
+	Args:  minImages(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := tagPath(args[0])
+		err := editImages(args[1:], func(i *img.Img) error {
+			tags := i.Tags()
+			tags.Add(path...)
+			i.SetTags(tags)
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove path images...",
+	Short: "remove a tag and its children",
+	Args:  minImages(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := tagPath(args[0])
+		err := editImages(args[1:], func(i *img.Img) error {
+			tags := i.Tags()
+			if tags.Remove(path...) {
+				i.SetTags(tags)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename from to images...",
+	Short: "move a tag and its children to a new path",
+	Args:  minImages(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to := tagPath(args[0]), tagPath(args[1])
+		err := editImages(args[2:], func(i *img.Img) error {
+			tags := i.Tags()
+			if tags.Rename(from, to) {
+				i.SetTags(tags)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	for _, c := range []*cobra.Command{tagAddCmd, tagRemoveCmd, tagRenameCmd} {
+		addEditFlags(c)
+		tagCmd.AddCommand(c)
+	}
+}
+
+func tagPath(arg string) []string {
+	paths := img.FromPaths([]string{arg}, "").Paths()
+	if len(paths) == 0 {
+		log.Fatal(fmt.Errorf("empty tag path %q", arg))
+	}
+	return paths[0]
+}
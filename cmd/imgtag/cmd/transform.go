@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateBy   int
+	flipDir    string
+	transpose  bool
+	transverse bool
+	autoOrient bool
+	cropTo     string
+)
+
+// transformCmd represents the transform command
+var transformCmd = &cobra.Command{
+	Use:   "transform [flags] jpegs...",
+	Short: "rotate, flip or crop jpegs without recompressing them",
+	Long: `Transform rotates, flips or crops jpegs by rearranging their DCT
+coefficients, so no quality is lost. Operations are relative to the image as
+displayed: the EXIF orientation is applied first and then reset.
+
+Edges that aren't a whole MCU, usually 8 or 16 pixels, are trimmed when they
+would move, and the corner of a crop is moved up and left to an MCU
+boundary.`,
+	Args: minImages(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ops, err := transformOps()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if editDir != "" {
+			err := os.MkdirAll(editDir, 0755)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		names, err := listImages(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range names {
+			err := transformFile(name, ops)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(transformCmd)
+	transformCmd.Flags().IntVar(&rotateBy, "rotate", 0, "rotate clockwise by 90, 180 or 270 degrees")
+	transformCmd.Flags().StringVar(&flipDir, "flip", "", "flip h(orizontally) or v(ertically)")
+	transformCmd.Flags().BoolVar(&transpose, "transpose", false, "flip along the top left to bottom right diagonal")
+	transformCmd.Flags().BoolVar(&transverse, "transverse", false, "flip along the top right to bottom left diagonal")
+	transformCmd.Flags().BoolVar(&autoOrient, "auto", false, "apply the EXIF orientation")
+	transformCmd.Flags().StringVar(&cropTo, "crop", "", "crop to WxH+X+Y")
+	transformCmd.Flags().StringVarP(&editDir, "output", "o", "", "save the transformed images to this directory instead of in place")
+}
+
+// transformOps returns the transforms named by the flags, with the crop
+// last.
+func transformOps() ([]img.Transform, error) {
+	var ops []img.Transform
+	switch rotateBy {
+	case 0:
+	case 90:
+		ops = append(ops, img.Rotate90)
+	case 180:
+		ops = append(ops, img.Rotate180)
+	case 270:
+		ops = append(ops, img.Rotate270)
+	default:
+		return nil, fmt.Errorf("can't rotate by %d, use 90, 180 or 270", rotateBy)
+	}
+	switch flipDir {
+	case "":
+	case "h":
+		ops = append(ops, img.FlipH)
+	case "v":
+		ops = append(ops, img.FlipV)
+	default:
+		return nil, fmt.Errorf("can't flip %q, use h or v", flipDir)
+	}
+	if transpose {
+		ops = append(ops, img.Transpose)
+	}
+	if transverse {
+		ops = append(ops, img.Transverse)
+	}
+	if autoOrient {
+		ops = append(ops, img.AutoOrient)
+	}
+	if cropTo != "" {
+		var w, h, x, y int
+		_, err := fmt.Sscanf(cropTo, "%dx%d+%d+%d", &w, &h, &x, &y)
+		if err != nil {
+			return nil, fmt.Errorf("crop %q isn't WxH+X+Y", cropTo)
+		}
+		ops = append(ops, img.Crop(image.Rect(x, y, x+w, y+h)))
+	}
+	if len(ops) == 0 {
+		return nil, errors.New("no transform given")
+	}
+	return ops, nil
+}
+
+// transformFile applies ops to name, or to a copy in --output.
+func transformFile(name string, ops []img.Transform) error {
+	if editDir != "" {
+		out := filepath.Join(editDir, filepath.Base(name))
+		err := copyFile(name, out)
+		if err != nil {
+			return err
+		}
+		name = out
+	}
+	for _, op := range ops {
+		err := img.LosslessTransform(name, op)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst. Nothing is copied when dst is src, so that the
+// caller works on the file in place.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(info, dstInfo) {
+		return nil
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/ohzqq/img"
+)
+
+func TestTransformFile(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(func() { editDir = "" })
+	dir := c.TempDir()
+	name := filepath.Join(dir, "photo.jpg")
+	f, err := os.Create(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jpeg.Encode(f, image.NewGray(image.Rect(0, 0, 32, 16)), nil), qt.IsNil)
+	c.Assert(f.Close(), qt.IsNil)
+
+	size := func(name string) image.Point {
+		f, err := os.Open(name)
+		c.Assert(err, qt.IsNil)
+		defer f.Close()
+		cfg, err := jpeg.DecodeConfig(f)
+		c.Assert(err, qt.IsNil)
+		return image.Pt(cfg.Width, cfg.Height)
+	}
+
+	out := c.TempDir()
+	editDir = out
+	c.Assert(transformFile(name, []img.Transform{img.Rotate90}), qt.IsNil)
+	c.Assert(size(filepath.Join(out, "photo.jpg")), qt.Equals, image.Pt(16, 32))
+	c.Assert(size(name), qt.Equals, image.Pt(32, 16))
+
+	// an --output that is the image's own directory transforms it in place
+	for _, d := range []string{dir, filepath.Join(dir, "."), dir + string(filepath.Separator)} {
+		editDir = d
+		c.Assert(transformFile(name, []img.Transform{img.Rotate90}), qt.IsNil)
+	}
+	c.Assert(size(name), qt.Equals, image.Pt(16, 32))
+}
+
+func TestCopyFile(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	name := filepath.Join(dir, "a.jpg")
+	c.Assert(os.WriteFile(name, []byte("pixels"), 0644), qt.IsNil)
+	c.Assert(os.Symlink("a.jpg", filepath.Join(dir, "link.jpg")), qt.IsNil)
+
+	for _, dst := range []string{
+		filepath.Join(dir, "b.jpg"),
+		name,
+		filepath.Join(dir, ".", "a.jpg"),
+		filepath.Join(dir, "link.jpg"),
+	} {
+		c.Assert(copyFile(name, dst), qt.IsNil)
+		b, err := os.ReadFile(dst)
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(b), qt.Equals, "pixels", qt.Commentf("%s", dst))
+	}
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ohzqq/img"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recursive      bool
+	includeGlobs   []string
+	excludeGlobs   []string
+	followSymlinks bool
+	filesFrom      string
+	nullSep        bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&recursive, "recursive", "r", false, "walk directories recursively")
+	rootCmd.PersistentFlags().StringSliceVar(&includeGlobs, "include", nil, "only use images matching these globs")
+	rootCmd.PersistentFlags().StringSliceVar(&excludeGlobs, "exclude", nil, "skip images and directories matching these globs")
+	rootCmd.PersistentFlags().BoolVar(&followSymlinks, "follow-symlinks", false, "follow symlinks found in directories")
+	rootCmd.PersistentFlags().StringVar(&filesFrom, "files-from", "", "read image names from this file, one per line, or - for stdin")
+	rootCmd.PersistentFlags().BoolVar(&nullSep, "null", false, "names read from files are separated by NUL, as written by find -print0")
+}
+
+// minImages is like cobra.MinimumNArgs for commands whose last arguments are
+// images, which may instead come from --files-from.
+func minImages(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if filesFrom != "" {
+			return cobra.MinimumNArgs(n-1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(n)(cmd, args)
+	}
+}
+
+// listImages expands the image arguments of a command. Directories are
+// listed, or walked with --recursive, and "-" and --files-from read names
+// from stdin or a file. Files that aren't a supported image, or that the
+// globs leave out, are skipped.
+func listImages(args []string) ([]string, error) {
+	l := &imageLister{seen: map[string]bool{}, visited: map[string]bool{}}
+	for _, arg := range args {
+		var err error
+		if arg == "-" {
+			err = l.readNames(os.Stdin)
+		} else {
+			err = l.add(arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if filesFrom != "" {
+		err := l.readNamesFrom(filesFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(l.files) == 0 {
+		return nil, errors.New("no images found")
+	}
+	return l.files, nil
+}
+
+// metaImages is like listImages for the commands that read or write
+// metadata. Images in a format without metadata are reported and skipped.
+func metaImages(args []string) ([]string, error) {
+	names, err := listImages(args)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, name := range names {
+		if !img.HasMeta(name) {
+			log.Printf("skipping %s: %s images have no metadata", name, strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), "."))
+			continue
+		}
+		out = append(out, name)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no images found")
+	}
+	return out, nil
+}
+
+type imageLister struct {
+	files []string
+	seen  map[string]bool
+	// visited holds the real paths of the directories walked, so that
+	// symlinks can't loop.
+	visited map[string]bool
+	stdin   bool
+}
+
+func (l *imageLister) readNamesFrom(name string) error {
+	if name == "-" {
+		return l.readNames(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return l.readNames(f)
+}
+
+// readNames adds the names listed in r, which are separated by newlines or
+// with --null by NUL.
+func (l *imageLister) readNames(r io.Reader) error {
+	if r == os.Stdin {
+		if l.stdin {
+			return nil
+		}
+		l.stdin = true
+	}
+	sc := bufio.NewScanner(r)
+	if nullSep {
+		sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+			if i := bytes.IndexByte(data, 0); i >= 0 {
+				return i + 1, data[:i], nil
+			}
+			if atEOF && len(data) > 0 {
+				return len(data), data, nil
+			}
+			return 0, nil, nil
+		})
+	}
+	for sc.Scan() {
+		name := strings.TrimSuffix(sc.Text(), "\r")
+		if name == "" {
+			continue
+		}
+		err := l.add(name)
+		if err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// add adds a named image, or the images in a directory. Named symlinks are
+// always followed.
+func (l *imageLister) add(name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return l.walk(name)
+	}
+	if !img.IsValidFormat(name) {
+		log.Printf("skipping %s: not a supported image", name)
+		return nil
+	}
+	l.addFile(name)
+	return nil
+}
+
+func (l *imageLister) walk(dir string) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if l.visited[real] {
+		return nil
+	}
+	l.visited[real] = true
+	// WalkDir doesn't follow a root that is a symlink unless it ends in a
+	// separator.
+	root := dir
+	if real != filepath.Clean(dir) {
+		root += string(filepath.Separator)
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if matchAny(excludeGlobs, path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !followSymlinks {
+				return nil
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				// a broken link
+				return nil
+			}
+			if info.IsDir() {
+				if recursive {
+					return l.walk(path)
+				}
+				return nil
+			}
+		}
+		if img.IsValidFormat(path) {
+			l.addFile(path)
+		}
+		return nil
+	})
+}
+
+// addFile adds an image that the globs let through, once.
+func (l *imageLister) addFile(name string) {
+	if len(includeGlobs) > 0 && !matchAny(includeGlobs, name) {
+		return
+	}
+	if matchAny(excludeGlobs, name) || l.seen[name] {
+		return
+	}
+	l.seen[name] = true
+	l.files = append(l.files, name)
+}
+
+// matchAny reports whether any of the globs matches the end of the path, so
+// "*.jpg" matches the file name and "thumbs/*" a file in any thumbs
+// directory.
+func matchAny(globs []string, path string) bool {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+	for _, glob := range globs {
+		glob = filepath.ToSlash(glob)
+		for k := range parts {
+			ok, err := filepath.Match(glob, strings.Join(parts[k:], "/"))
+			if err != nil {
+				log.Fatal(fmt.Errorf("bad glob %q: %w", glob, err))
+			}
+			if ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// imageTree makes a directory of empty files, which is all listImages looks
+// at, and returns its path.
+func imageTree(c *qt.C, names ...string) string {
+	dir := c.TempDir()
+	for _, name := range names {
+		name = filepath.Join(dir, name)
+		c.Assert(os.MkdirAll(filepath.Dir(name), 0755), qt.IsNil)
+		c.Assert(os.WriteFile(name, nil, 0644), qt.IsNil)
+	}
+	return dir
+}
+
+// resetWalkFlags restores the flags of listImages when the test ends.
+func resetWalkFlags(c *qt.C) {
+	c.Cleanup(func() {
+		recursive, followSymlinks, nullSep = false, false, false
+		includeGlobs, excludeGlobs = nil, nil
+		filesFrom = ""
+	})
+}
+
+func TestListImages(t *testing.T) {
+	c := qt.New(t)
+	root := imageTree(c,
+		"photos/a.jpg",
+		"photos/b.PNG",
+		"photos/c.gif",
+		"photos/index.html",
+		"photos/notes.txt",
+		"photos/trip/d.webp",
+		"photos/trip/thumbs/e.jpg",
+		"other/f.tiff",
+	)
+	dir := filepath.Join(root, "photos")
+	c.Assert(os.Symlink(filepath.Join(root, "other"), filepath.Join(dir, "other")), qt.IsNil)
+	c.Assert(os.Symlink("a.jpg", filepath.Join(dir, "g.jpg")), qt.IsNil)
+	c.Assert(os.Symlink(".", filepath.Join(dir, "loop")), qt.IsNil)
+	c.Assert(os.Symlink("missing.jpg", filepath.Join(dir, "broken.jpg")), qt.IsNil)
+
+	tests := []struct {
+		name      string
+		recursive bool
+		follow    bool
+		include   []string
+		exclude   []string
+		want      []string
+	}{
+		{
+			name: "directory",
+			want: []string{"a.jpg", "b.PNG", "c.gif", "index.html"},
+		},
+		{
+			name:      "recursive",
+			recursive: true,
+			want:      []string{"a.jpg", "b.PNG", "c.gif", "index.html", "trip/d.webp", "trip/thumbs/e.jpg"},
+		},
+		{
+			name:      "exclude file",
+			recursive: true,
+			exclude:   []string{"thumbs/*"},
+			want:      []string{"a.jpg", "b.PNG", "c.gif", "index.html", "trip/d.webp"},
+		},
+		{
+			name:      "exclude directory",
+			recursive: true,
+			exclude:   []string{"trip"},
+			want:      []string{"a.jpg", "b.PNG", "c.gif", "index.html"},
+		},
+		{
+			name:      "include",
+			recursive: true,
+			include:   []string{"*.jpg"},
+			want:      []string{"a.jpg", "trip/thumbs/e.jpg"},
+		},
+		{
+			name:   "follow symlinks",
+			follow: true,
+			want:   []string{"a.jpg", "b.PNG", "c.gif", "g.jpg", "index.html"},
+		},
+		{
+			name:      "follow symlinks recursively",
+			recursive: true,
+			follow:    true,
+			want:      []string{"a.jpg", "b.PNG", "c.gif", "g.jpg", "index.html", "other/f.tiff", "trip/d.webp", "trip/thumbs/e.jpg"},
+		},
+	}
+	for _, test := range tests {
+		c.Run(test.name, func(c *qt.C) {
+			resetWalkFlags(c)
+			recursive, followSymlinks = test.recursive, test.follow
+			includeGlobs, excludeGlobs = test.include, test.exclude
+			got, err := listImages([]string{dir})
+			c.Assert(err, qt.IsNil)
+			want := make([]string, len(test.want))
+			for k, name := range test.want {
+				want[k] = filepath.Join(dir, name)
+			}
+			c.Assert(got, qt.DeepEquals, want)
+		})
+	}
+
+	c.Run("named files", func(c *qt.C) {
+		resetWalkFlags(c)
+		a := filepath.Join(dir, "a.jpg")
+		got, err := listImages([]string{a, filepath.Join(dir, "notes.txt"), filepath.Join(dir, "g.jpg"), a})
+		c.Assert(err, qt.IsNil)
+		c.Assert(got, qt.DeepEquals, []string{a, filepath.Join(dir, "g.jpg")})
+
+		_, err = listImages([]string{filepath.Join(dir, "notes.txt")})
+		c.Assert(err, qt.ErrorMatches, "no images found")
+		_, err = listImages([]string{filepath.Join(dir, "missing.jpg")})
+		c.Assert(err, qt.ErrorIs, os.ErrNotExist)
+	})
+}
+
+func TestMetaImages(t *testing.T) {
+	c := qt.New(t)
+	resetWalkFlags(c)
+	dir := imageTree(c, "a.jpg", "b.gif", "c.bmp", "d.pdf", "e.html", "f.cbz", "g.webp")
+	got, err := metaImages([]string{dir})
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, []string{filepath.Join(dir, "a.jpg"), filepath.Join(dir, "f.cbz"), filepath.Join(dir, "g.webp")})
+
+	_, err = metaImages([]string{filepath.Join(dir, "b.gif")})
+	c.Assert(err, qt.ErrorMatches, "no images found")
+}
+
+func TestReadNames(t *testing.T) {
+	c := qt.New(t)
+	dir := imageTree(c, "a b.jpg", "c.png", "d.txt")
+	names := []string{filepath.Join(dir, "a b.jpg"), filepath.Join(dir, "c.png")}
+
+	c.Run("lines", func(c *qt.C) {
+		resetWalkFlags(c)
+		l := &imageLister{seen: map[string]bool{}, visited: map[string]bool{}}
+		in := names[0] + "\r\n\n" + filepath.Join(dir, "d.txt") + "\n" + names[1]
+		c.Assert(l.readNames(strings.NewReader(in)), qt.IsNil)
+		c.Assert(l.files, qt.DeepEquals, names)
+	})
+
+	c.Run("null", func(c *qt.C) {
+		resetWalkFlags(c)
+		nullSep = true
+		l := &imageLister{seen: map[string]bool{}, visited: map[string]bool{}}
+		in := names[0] + "\x00" + names[1] + "\x00"
+		c.Assert(l.readNames(strings.NewReader(in)), qt.IsNil)
+		c.Assert(l.files, qt.DeepEquals, names)
+	})
+
+	c.Run("files from", func(c *qt.C) {
+		resetWalkFlags(c)
+		list := filepath.Join(c.TempDir(), "list")
+		c.Assert(os.WriteFile(list, []byte(strings.Join(names, "\x00")), 0644), qt.IsNil)
+		nullSep = true
+		filesFrom = list
+		got, err := listImages(nil)
+		c.Assert(err, qt.IsNil)
+		c.Assert(got, qt.DeepEquals, names)
+	})
+}
+
+func TestMatchAny(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{"*.jpg", "photos/a.jpg", true},
+		{"*.jpg", "photos/a.png", false},
+		{"thumbs/*", "photos/thumbs/a.jpg", true},
+		{"thumbs/*", "photos/thumbs", false},
+		{"thumbs", "photos/thumbs", true},
+		{"photos/*/a.jpg", "photos/trip/a.jpg", true},
+		{"a.jpg", "photos/ba.jpg", false},
+		{"*.jpg", "./photos/../a.jpg", true},
+	}
+	for _, test := range tests {
+		if got := matchAny([]string{test.glob}, test.path); got != test.want {
+			t.Errorf("matchAny(%q, %q) = %v, want %v", test.glob, test.path, got, test.want)
+		}
+	}
+}
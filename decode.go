@@ -1,12 +1,13 @@
 package img
 
 import (
-	"fmt"
+	"bytes"
 	"image"
 	"io"
-	"os"
+	"math"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/bep/imagemeta"
 	"github.com/evanoberholster/imagemeta/xmp"
@@ -14,17 +15,31 @@ import (
 )
 
 type Decoder struct {
-	r        io.Reader
-	Fmt      Format
-	withMeta bool
-	opts     imagemeta.Options
+	r         io.Reader
+	Fmt       Format
+	withMeta  bool
+	opts      imagemeta.Options
+	maxPixels int
+	maxWidth  int
+	maxHeight int
+	maxFrames int
+	maxBytes  int64
+	sidecar   Precedence
+	idScheme  IDScheme
+	idRoot    string
+	// docID is the xmpMM:DocumentID read by decodeMeta.
+	docID string
 }
 
-func NewDecoder(r io.Reader) *Decoder {
-	return &Decoder{
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	dec := &Decoder{
 		r:    r,
 		opts: imagemeta.Options{},
 	}
+	for _, opt := range opts {
+		opt(dec)
+	}
+	return dec
 }
 
 func newDecoder(f Format, opts ...DecodeOption) *Decoder {
@@ -38,94 +53,196 @@ func newDecoder(f Format, opts ...DecodeOption) *Decoder {
 	return dec
 }
 
+// Decode decodes an image of format f, checking any limits set on the
+// decoder before the image is allocated.
 func (dec *Decoder) Decode(f Format) (image.Image, error) {
-	return f.Decode(dec.r)
+	r := dec.r
+	if dec.hasLimits() {
+		var err error
+		r, err = dec.checkLimits(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f.decode(r)
 }
 
-func Open(file string, withMeta bool) (*Img, error) {
+// Open decodes the image in file, and its metadata if withMeta is set.
+func Open(file string, withMeta bool, opts ...DecodeOption) (*Img, error) {
 	img, err := New(file)
 	if err != nil {
 		return nil, err
 	}
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	dec := NewDecoder(f)
-	defer f.Close()
-	i, err := dec.Decode(img.Fmt)
+	img.withMeta = withMeta
+	err = img.Open(opts...)
 	if err != nil {
 		return nil, err
 	}
-	img.img = i
-	if withMeta {
-		err := img.ReadMeta()
-		if err != nil {
-			return img, err
-		}
-	}
 	return img, nil
 }
 
+// DecodeXMP reads the Dublin Core of an image from its XMP packet, falling
+// back to the ACDSee, Photoshop and EXIF fields used by other taggers.
 func (dec *Decoder) DecodeXMP(r io.ReadSeeker) (xmp.XMP, error) {
+	x, _, err := dec.decodeMeta(r)
+	return x, err
+}
+
+// decodeMeta reads the Dublin Core and the tag tree of an image. Tags are
+// merged from every hierarchical keyword dialect found in the XMP packet.
+// IPTC datasets fill in any fields missing from the XMP.
+func (dec *Decoder) decodeMeta(r io.ReadSeeker) (xmp.XMP, *Tags, error) {
 	dec.withMeta = true
-	var tags imagemeta.Tags
+	var (
+		tags  imagemeta.Tags
+		props xmpProps
+		iptc  = iptcProps{}
+	)
 	dec.opts.HandleTag = func(ti imagemeta.TagInfo) error {
-		if slices.Contains(imgMetaFieldsStr, ti.Tag) {
+		if ti.Source == imagemeta.IPTC {
+			iptc.add(ti.Tag, ti.Value)
+			return nil
+		}
+		if slices.Contains(imgMetaFieldsStr, ti.Tag) || slices.Contains(exifCaptureTags, ti.Tag) || slices.Contains(exifGPSTags, ti.Tag) {
 			tags.Add(ti)
 			return nil
 		}
 		return nil
 	}
+	dec.opts.HandleXMP = func(xr io.Reader) error {
+		b, err := io.ReadAll(xr)
+		if err != nil {
+			return err
+		}
+		props, err = parseXMP(bytes.NewReader(b))
+		return err
+	}
 	dec.opts.R = r
-	dec.opts.Sources = imagemeta.EXIF | imagemeta.XMP
+	dec.opts.Sources = imagemeta.EXIF | imagemeta.IPTC | imagemeta.XMP
 
 	err := imagemeta.Decode(dec.opts)
 	if err != nil {
-		return xmp.XMP{}, fmt.Errorf("imagemeta decode err %w\n", err)
+		offset, serr := r.Seek(0, io.SeekCurrent)
+		if serr != nil {
+			offset = -1
+		}
+		return xmp.XMP{}, NewTags(), formatErr(dec.Fmt, "decode meta", offset, err)
+	}
+
+	if props == nil && (dec.Fmt == JPEG || dec.Fmt == PNG) {
+		props, err = dec.findXMP(r)
+		if err != nil {
+			return xmp.XMP{}, NewTags(), formatErr(dec.Fmt, "decode meta", -1, err)
+		}
+	}
+
+	if id := props[xmpDocumentID]; len(id) > 0 {
+		dec.docID = id[0]
+	}
+	x, hTags, err := props.meta()
+	if err != nil {
+		return x, hTags, formatErr(dec.Fmt, "decode meta", -1, err)
+	}
+	x.Exif.DateTimeOriginal = props.captureTime()
+	if x.Exif.DateTimeOriginal.IsZero() {
+		x.Exif.DateTimeOriginal = exifCaptureTime(tags.EXIF())
+	}
+	x.Exif.GPSLatitude, x.Exif.GPSLongitude = props.gps()
+	if x.Exif.GPSLatitude == 0 && x.Exif.GPSLongitude == 0 {
+		x.Exif.GPSLatitude, x.Exif.GPSLongitude = exifGPS(tags.EXIF())
+	}
+	ix, iTags := iptc.meta()
+	x, hTags = mergeMeta(x, hTags, ix, iTags)
+	if len(x.DC.Description) == 0 {
+		if ti, ok := tags.EXIF()[ImageDescription.String()]; ok {
+			if d := strings.TrimSpace(cast.ToString(ti.Value)); d != "" {
+				x.DC.Description = []string{d}
+			}
+		}
 	}
+	if dcIsEmpty(x.DC) {
+		return x, hTags, ErrNoMetadata
+	}
+	return x, hTags, nil
+}
 
-	x := xmp.XMP{DC: xmp.DublinCore{}}
-	for n, ti := range tags.All() {
-		switch n {
-		case strings.ToLower(Categories.String()):
-			hTags, err := UnmarshalHTags([]byte(cast.ToString(ti.Value)))
-			if err != nil {
-				return x, err
+// exifCaptureTags are the EXIF tags read for the capture time.
+var exifCaptureTags = []string{"DateTimeOriginal", "OffsetTimeOriginal", "CreateDate", "OffsetTimeDigitized"}
+
+// exifCaptureTime parses DateTimeOriginal, or CreateDate, in the zone of its
+// offset tag. Without an offset the time is taken to be UTC.
+func exifCaptureTime(exif map[string]imagemeta.TagInfo) time.Time {
+	for _, tag := range [][2]string{{"DateTimeOriginal", "OffsetTimeOriginal"}, {"CreateDate", "OffsetTimeDigitized"}} {
+		ti, ok := exif[tag[0]]
+		if !ok {
+			continue
+		}
+		v := strings.TrimSpace(cast.ToString(ti.Value))
+		if off, ok := exif[tag[1]]; ok {
+			t, err := time.Parse("2006:01:02 15:04:05-07:00", v+strings.TrimSpace(cast.ToString(off.Value)))
+			if err == nil {
+				return t
 			}
-			x.DC.Subject = hTags.StringSlice()
-		case strings.ToLower(Caption.String()):
-			x.DC.Title = []string{cast.ToString(ti.Value)}
-		case Credit.String():
-			x.DC.Creator = []string{cast.ToString(ti.Value)}
-		case ImageDescription.String():
-			x.DC.Description = []string{cast.ToString(ti.Value)}
+		}
+		t, err := time.Parse("2006:01:02 15:04:05", v)
+		if err == nil {
+			return t
 		}
 	}
-	return x, nil
+	return time.Time{}
 }
 
-// open loads an image from file.
-// https://github.com/sunshineplan/imgconv
-func open(file string) (image.Image, error) {
-	img, err := New(file)
+// exifGPSTags are the EXIF tags read for the location.
+var exifGPSTags = []string{"GPSLatitude", "GPSLatitudeRef", "GPSLongitude", "GPSLongitudeRef"}
+
+// exifGPS returns the location in decimal degrees, south and west being
+// negative.
+func exifGPS(exif map[string]imagemeta.TagInfo) (lat, long float64) {
+	coord := func(tag, neg string) float64 {
+		ti, ok := exif[tag]
+		if !ok {
+			return 0
+		}
+		v := cast.ToFloat64(ti.Value)
+		if math.IsNaN(v) {
+			return 0
+		}
+		if ref, ok := exif[tag+"Ref"]; ok && strings.EqualFold(strings.TrimSpace(cast.ToString(ref.Value)), neg) {
+			v = -v
+		}
+		return v
+	}
+	return coord("GPSLatitude", "S"), coord("GPSLongitude", "W")
+}
+
+// findXMP looks for a packet that imagemeta skipped.
+func (dec *Decoder) findXMP(r io.ReadSeeker) (xmpProps, error) {
+	_, err := r.Seek(0, io.SeekStart)
 	if err != nil {
 		return nil, err
 	}
-	dec := newDecoder(img.Fmt)
-	f, err := os.Open(file)
+	b, err := io.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-	return dec.Fmt.Decode(f)
+	packet, err := findXMP(dec.Fmt, b)
+	if err != nil || packet == nil {
+		return nil, err
+	}
+	return parseXMP(bytes.NewReader(packet))
+}
+
+// open loads an image from file.
+// https://github.com/sunshineplan/imgconv
+func open(file string, opts ...DecodeOption) (image.Image, error) {
+	return openFS(osFS, file, opts...)
 }
 
 // OpenAll loads images from files.
-func OpenAll(files []string) ([]image.Image, error) {
+func OpenAll(files []string, opts ...DecodeOption) ([]image.Image, error) {
 	imgs := make([]image.Image, len(files))
 	for i, file := range files {
-		img, err := open(file)
+		img, err := open(file, opts...)
 		if err != nil {
 			return imgs, err
 		}
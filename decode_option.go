@@ -7,3 +7,98 @@ func WithMeta() DecodeOption {
 		dec.withMeta = true
 	}
 }
+
+// MaxPixels returns a DecodeOption that rejects images whose width * height,
+// as read from the header, is greater than n.
+func MaxPixels(n int) DecodeOption {
+	return func(dec *Decoder) {
+		dec.maxPixels = n
+	}
+}
+
+// MaxWidth returns a DecodeOption that rejects images wider than n pixels.
+func MaxWidth(n int) DecodeOption {
+	return func(dec *Decoder) {
+		dec.maxWidth = n
+	}
+}
+
+// MaxHeight returns a DecodeOption that rejects images taller than n pixels.
+func MaxHeight(n int) DecodeOption {
+	return func(dec *Decoder) {
+		dec.maxHeight = n
+	}
+}
+
+// MaxFrames returns a DecodeOption that rejects GIF and WEBP animations or
+// PDF and CBZ documents with more than n frames or pages.
+func MaxFrames(n int) DecodeOption {
+	return func(dec *Decoder) {
+		dec.maxFrames = n
+	}
+}
+
+// MaxBytes returns a DecodeOption that stops reading the input after n bytes
+// and rejects it.
+func MaxBytes(n int64) DecodeOption {
+	return func(dec *Decoder) {
+		dec.maxBytes = n
+	}
+}
+
+// Precedence decides which of an image's embedded metadata and its XMP
+// sidecar wins when both set a field.
+type Precedence int
+
+const (
+	// NoSidecar ignores sidecars.
+	NoSidecar Precedence = iota
+	// PreferEmbedded fills fields missing from the image from the sidecar.
+	PreferEmbedded
+	// PreferSidecar fills fields missing from the sidecar from the image.
+	PreferSidecar
+)
+
+// IDScheme decides how the Dublin Core identifier of an image is set when
+// its metadata is read.
+type IDScheme int
+
+const (
+	// IDPath is the file name the image was opened with.
+	IDPath IDScheme = iota
+	// IDRelative is the file name relative to the root set with IDRoot, or
+	// to the working directory, with forward slashes.
+	IDRelative
+	// IDFileHash is "sha256:" followed by the hex SHA-256 of the file.
+	IDFileHash
+	// IDPixelHash is "pixels:sha256:" followed by the hex SHA-256 of the
+	// decoded pixels, so it doesn't change when the metadata is edited.
+	IDPixelHash
+	// IDDocument is the xmpMM:DocumentID. Images without one are given a
+	// random UUID, which is written when the image is saved.
+	IDDocument
+)
+
+// Identifier returns a DecodeOption that sets the identifier scheme.
+func Identifier(s IDScheme) DecodeOption {
+	return func(dec *Decoder) {
+		dec.idScheme = s
+	}
+}
+
+// IDRoot returns a DecodeOption that sets the directory IDRelative
+// identifiers are relative to. It doesn't change the scheme.
+func IDRoot(root string) DecodeOption {
+	return func(dec *Decoder) {
+		dec.idRoot = root
+	}
+}
+
+// Sidecar returns a DecodeOption that merges the metadata of an image with
+// its photo.xmp or photo.jpg.xmp sidecar, as written by Lightroom and
+// darktable.
+func Sidecar(p Precedence) DecodeOption {
+	return func(dec *Decoder) {
+		dec.sidecar = p
+	}
+}
@@ -1,8 +1,21 @@
 package img
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 )
@@ -30,3 +43,170 @@ func TestDecodeAnimatedWebp(t *testing.T) {
 	_, err = dec.Fmt.DecodeAnimatedWebP(f)
 	c.Assert(err, qt.IsNil)
 }
+
+func TestDecodeLimits(t *testing.T) {
+	c := qt.New(t)
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 32)))
+	c.Assert(err, qt.IsNil)
+	data := buf.Bytes()
+
+	tests := []struct {
+		opt   DecodeOption
+		limit string
+	}{
+		{MaxPixels(1000), "pixels"},
+		{MaxWidth(32), "width"},
+		{MaxHeight(16), "height"},
+		{MaxBytes(10), "bytes"},
+	}
+	for _, test := range tests {
+		_, err := PNG.Decode(bytes.NewReader(data), test.opt)
+		c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+		var lerr *LimitError
+		c.Assert(errors.As(err, &lerr), qt.IsTrue)
+		c.Assert(lerr.Limit, qt.Equals, test.limit)
+	}
+
+	i, err := NewDecoder(bytes.NewReader(data), MaxPixels(64*32), MaxFrames(1)).Decode(PNG)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.Bounds().Dx(), qt.Equals, 64)
+
+	// only a byte limit reads the whole input before decoding
+	errTail := errors.New("read past the header")
+	tail := func() io.Reader {
+		return io.MultiReader(bytes.NewReader(data), iotest.ErrReader(errTail))
+	}
+	_, err = NewDecoder(tail(), MaxPixels(64*32), MaxFrames(1)).checkLimits(PNG)
+	c.Assert(err, qt.IsNil)
+	_, err = NewDecoder(tail(), MaxBytes(1<<20)).checkLimits(PNG)
+	c.Assert(err, qt.ErrorIs, errTail)
+
+	name := filepath.Join(t.TempDir(), "limit.png")
+	c.Assert(os.WriteFile(name, data, 0644), qt.IsNil)
+	_, err = Open(name, true, MaxWidth(32))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	_, err = Open(name, false, MaxWidth(32))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	_, err = OpenFS(DirFS(filepath.Dir(name)), "limit.png", MaxHeight(16))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	_, err = OpenAll([]string{name}, MaxBytes(10))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+	_, err = PNG.DecodeAll(bytes.NewReader(data), MaxPixels(1000))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+}
+
+func TestCountGIFFrames(t *testing.T) {
+	c := qt.New(t)
+	pal := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{}
+	for range 3 {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 8, 8), pal))
+		anim.Delay = append(anim.Delay, 0)
+	}
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, anim)
+	c.Assert(err, qt.IsNil)
+
+	n, err := countGIFFrames(buf.Bytes())
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 3)
+
+	_, err = GIF.Decode(bytes.NewReader(buf.Bytes()), MaxFrames(2))
+	c.Assert(err, qt.ErrorIs, ErrLimitExceeded)
+}
+
+func TestCaptureTime(t *testing.T) {
+	c := qt.New(t)
+	le := binary.LittleEndian
+	exif := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	entry := func(tag, typ uint16, count, val uint32) {
+		exif = le.AppendUint16(exif, tag)
+		exif = le.AppendUint16(exif, typ)
+		exif = le.AppendUint32(exif, count)
+		exif = le.AppendUint32(exif, val)
+	}
+	exif = le.AppendUint16(exif, 1)
+	entry(exifIFDTag, 4, 1, 26)
+	exif = le.AppendUint32(exif, 0)
+	exif = le.AppendUint16(exif, 2)
+	entry(0x9003, tiffASCII, 20, 56)
+	entry(0x9011, tiffASCII, 7, 76)
+	exif = le.AppendUint32(exif, 0)
+	exif = append(exif, "2021:03:04 05:06:07\x00-05:00\x00"...)
+
+	var buf bytes.Buffer
+	c.Assert(jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil), qt.IsNil)
+	b, err := embedEXIF(JPEG, buf.Bytes(), exif)
+	c.Assert(err, qt.IsNil)
+	i, err := FromBytes(b)
+	c.Assert(err, qt.IsNil)
+	want := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("", -5*3600))
+	c.Assert(i.CaptureTime().Equal(want), qt.IsTrue, qt.Commentf("%v", i.CaptureTime()))
+
+	// the xmp is preferred
+	packet := marshalXMP([]xmpProp{{name: xmpCaptureTimes[1], values: []string{"2020-01-02T03:04:05Z"}}})
+	b, err = embedJPEGXMP(b, packet)
+	c.Assert(err, qt.IsNil)
+	i, err = FromBytes(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.CaptureTime(), qt.Equals, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	i, err = FromBytes(buf.Bytes())
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.CaptureTime().IsZero(), qt.IsTrue)
+}
+
+func TestGPS(t *testing.T) {
+	c := qt.New(t)
+	le := binary.LittleEndian
+	exif := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	entry := func(tag, typ uint16, count, val uint32) {
+		exif = le.AppendUint16(exif, tag)
+		exif = le.AppendUint16(exif, typ)
+		exif = le.AppendUint32(exif, count)
+		exif = le.AppendUint32(exif, val)
+	}
+	exif = le.AppendUint16(exif, 1)
+	entry(tiffGPSIFDTag, 4, 1, 26)
+	exif = le.AppendUint32(exif, 0)
+	exif = le.AppendUint16(exif, 4)
+	entry(0x0001, tiffASCII, 2, 'S')
+	entry(0x0002, 5, 3, 80)
+	entry(0x0003, tiffASCII, 2, 'W')
+	entry(0x0004, 5, 3, 104)
+	exif = le.AppendUint32(exif, 0)
+	for _, v := range []uint32{40, 26, 45, 79, 58, 30} {
+		exif = le.AppendUint32(exif, v)
+		exif = le.AppendUint32(exif, 1)
+	}
+
+	var buf bytes.Buffer
+	c.Assert(jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil), qt.IsNil)
+	b, err := embedEXIF(JPEG, buf.Bytes(), exif)
+	c.Assert(err, qt.IsNil)
+	i, err := FromBytes(b)
+	c.Assert(err, qt.IsNil)
+	lat, long, ok := i.GPS()
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(math.Abs(lat-(-(40+26.0/60+45.0/3600))) < 1e-9, qt.IsTrue, qt.Commentf("%v", lat))
+	c.Assert(math.Abs(long-(-(79+58.0/60+30.0/3600))) < 1e-9, qt.IsTrue, qt.Commentf("%v", long))
+
+	// the xmp is preferred
+	packet := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:exif="http://ns.adobe.com/exif/1.0/" exif:GPSLatitude="40,26.767N" exif:GPSLongitude="79,58,30E"/>
+</rdf:RDF></x:xmpmeta>`
+	b, err = embedJPEGXMP(b, []byte(packet))
+	c.Assert(err, qt.IsNil)
+	i, err = FromBytes(b)
+	c.Assert(err, qt.IsNil)
+	lat, long, ok = i.GPS()
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(math.Abs(lat-(40+26.767/60)) < 1e-9, qt.IsTrue, qt.Commentf("%v", lat))
+	c.Assert(math.Abs(long-(79+58.0/60+30.0/3600)) < 1e-9, qt.IsTrue, qt.Commentf("%v", long))
+
+	i, err = FromBytes(buf.Bytes())
+	c.Assert(err, qt.IsNil)
+	_, _, ok = i.GPS()
+	c.Assert(ok, qt.IsFalse)
+}
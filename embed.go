@@ -0,0 +1,818 @@
+package img
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+const (
+	jpegXMPHeader = "http://ns.adobe.com/xap/1.0/\x00"
+	pngXMPKeyword = "XML:com.adobe.xmp"
+	tiffXMPTag    = 700
+
+	tiffImageDescriptionTag = 270
+	tiffArtistTag           = 315
+	tiffCopyrightTag        = 33432
+	tiffIPTCTag             = 33723
+)
+
+// errXMPTooLarge is returned when a packet doesn't fit in a single JPEG APP1
+// segment. Extended XMP isn't supported.
+var errXMPTooLarge = errors.New("xmp packet too large for a jpeg segment")
+
+// embedXMP returns data with its XMP packet replaced by packet, leaving the
+// pixels and all other metadata untouched.
+func embedXMP(f Format, data, packet []byte) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch f {
+	case JPEG:
+		b, err = embedJPEGXMP(data, packet)
+	case PNG:
+		b, err = embedPNGXMP(data, packet)
+	case WEBP:
+		b, err = embedWEBPXMP(data, packet)
+	case TIFF:
+		b, err = embedTIFFXMP(data, packet)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, formatErr(f, "embed xmp", -1, err)
+	}
+	return b, nil
+}
+
+// findXMP returns the XMP packet in a JPEG or PNG file, if any. It covers
+// the cases imagemeta misses: PNG iTXt chunks and JPEG files whose first APP1
+// segment is XMP rather than EXIF.
+func findXMP(f Format, data []byte) ([]byte, error) {
+	switch f {
+	case JPEG:
+		segs, _, err := splitJPEG(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range segs {
+			if isJPEGXMP(s) {
+				return s.data[len(jpegXMPHeader):], nil
+			}
+		}
+	case PNG:
+		chunks, err := splitPNG(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chunks {
+			if isPNGXMP(c) {
+				return pngITXtText(c.data)
+			}
+		}
+	}
+	return nil, nil
+}
+
+const jpegEXIFHeader = "Exif\x00\x00"
+
+// findEXIF returns the EXIF block of a JPEG, PNG or WEBP file as a TIFF
+// structure, if any.
+func findEXIF(f Format, data []byte) ([]byte, error) {
+	switch f {
+	case JPEG:
+		segs, _, err := splitJPEG(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range segs {
+			if isJPEGEXIF(s) {
+				return s.data[len(jpegEXIFHeader):], nil
+			}
+		}
+	case PNG:
+		chunks, err := splitPNG(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chunks {
+			if c.typ == "eXIf" {
+				return c.data, nil
+			}
+		}
+	case WEBP:
+		chunks, err := splitWEBP(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chunks {
+			if c.fourcc == "EXIF" {
+				// some writers keep the JPEG header
+				return bytes.TrimPrefix(c.data, []byte(jpegEXIFHeader)), nil
+			}
+		}
+	case TIFF:
+		return tiffEXIF(data)
+	}
+	return nil, nil
+}
+
+// tiffEXIF makes an EXIF block of the metadata entries of a TIFF's IFD0,
+// with its EXIF and GPS IFDs, leaving out the image data, XMP and IPTC.
+func tiffEXIF(data []byte) ([]byte, error) {
+	bo, ifd, err := tiffHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	out := slices.Clone(data[:8])
+	out, _, err = copyIFD(out, data, bo, ifd, 0, func(tag uint16) bool {
+		if tag == tiffXMPTag || tag == tiffIPTCTag {
+			return false
+		}
+		return tag == tiffOrientationTag || !slices.Contains(tiffImageTags, tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bo.Uint16(out[8:]) == 0 {
+		return nil, nil
+	}
+	bo.PutUint32(out[4:], 8)
+	return out, nil
+}
+
+// copyIFD appends the entries of the IFD at off that keep reports to out,
+// with their values and the IFDs they point to, and returns the offset of
+// the copy. The copy has no next IFD.
+func copyIFD(out, b []byte, bo binary.ByteOrder, off, depth int, keep func(tag uint16) bool) ([]byte, int, error) {
+	if depth > 2 {
+		return nil, 0, &CorruptError{Offset: int64(off), Err: errors.New("bad tiff ifd offset")}
+	}
+	n, err := tiffIFD(b, bo, off)
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []int
+	for i := range n {
+		e := off + 2 + i*12
+		if keep(bo.Uint16(b[e:])) {
+			entries = append(entries, e)
+		}
+	}
+	if len(out)&1 == 1 {
+		out = append(out, 0)
+	}
+	start := len(out)
+	out = append(out, make([]byte, 2+len(entries)*12+4)...)
+	bo.PutUint16(out[start:], uint16(len(entries)))
+	for k, e := range entries {
+		dst := start + 2 + k*12
+		copy(out[dst:dst+12], b[e:e+12])
+		switch bo.Uint16(b[e:]) {
+		case exifIFDTag, tiffGPSIFDTag, tiffInteropIFDTag:
+			var sub int
+			var err error
+			out, sub, err = copyIFD(out, b, bo, int(bo.Uint32(b[e+8:])), depth+1, func(uint16) bool { return true })
+			if err != nil {
+				return nil, 0, err
+			}
+			bo.PutUint32(out[dst+8:], uint32(sub))
+			continue
+		}
+		if int(bo.Uint32(b[e+4:]))*tiffTypeSize(bo.Uint16(b[e+2:])) <= 4 {
+			continue
+		}
+		v := tiffEntryValue(b, bo, e)
+		if v == nil {
+			return nil, 0, &CorruptError{Offset: int64(e), Err: errors.New("bad tiff value offset")}
+		}
+		if len(out)&1 == 1 {
+			out = append(out, 0)
+		}
+		bo.PutUint32(out[dst+8:], uint32(len(out)))
+		out = append(out, v...)
+	}
+	return out, start, nil
+}
+
+// canEmbedEXIF reports whether embedEXIF supports format f.
+func canEmbedEXIF(f Format) bool {
+	return f == JPEG || f == PNG || f == WEBP
+}
+
+// embedEXIF returns data with its EXIF block replaced by exif, a TIFF
+// structure.
+func embedEXIF(f Format, data, exif []byte) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch f {
+	case JPEG:
+		b, err = embedJPEGEXIF(data, exif)
+	case PNG:
+		b, err = embedPNGEXIF(data, exif)
+	case WEBP:
+		b, err = embedWEBPEXIF(data, exif)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, formatErr(f, "embed exif", -1, err)
+	}
+	return b, nil
+}
+
+func isJPEGEXIF(s jpegSegment) bool {
+	return s.marker == 0xe1 && bytes.HasPrefix(s.data, []byte(jpegEXIFHeader))
+}
+
+// embedJPEGEXIF replaces the EXIF APP1 segment, placing it after JFIF.
+func embedJPEGEXIF(data, exif []byte) ([]byte, error) {
+	segs, scan, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	payload := append([]byte(jpegEXIFHeader), exif...)
+	if len(payload)+2 > 0xffff {
+		return nil, errors.New("exif too large for a jpeg segment")
+	}
+	segs = slices.DeleteFunc(segs, isJPEGEXIF)
+	at := 0
+	if len(segs) > 0 && segs[0].marker == 0xe0 {
+		at = 1
+	}
+	segs = slices.Insert(segs, at, jpegSegment{marker: 0xe1, data: payload})
+	return joinJPEG(segs, scan), nil
+}
+
+// embedPNGEXIF replaces the eXIf chunk, placing it before the image data.
+func embedPNGEXIF(data, exif []byte) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+	chunks = slices.DeleteFunc(chunks, func(c pngChunk) bool {
+		return c.typ == "eXIf"
+	})
+	at := slices.IndexFunc(chunks, func(c pngChunk) bool {
+		return c.typ == "IDAT"
+	})
+	if at < 0 {
+		return nil, &CorruptError{Offset: -1, Err: errors.New("missing png IDAT chunk")}
+	}
+	chunks = slices.Insert(chunks, at, pngChunk{typ: "eXIf", data: exif})
+	return joinPNG(chunks), nil
+}
+
+// embedWEBPEXIF replaces the EXIF chunk, placing it before any XMP.
+func embedWEBPEXIF(data, exif []byte) ([]byte, error) {
+	chunks, err := splitWEBP(data)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err = withVP8XFlag(chunks, vp8xEXIFFlag)
+	if err != nil {
+		return nil, err
+	}
+	chunks = slices.DeleteFunc(chunks, func(c riffChunk) bool {
+		return c.fourcc == "EXIF"
+	})
+	at := slices.IndexFunc(chunks, func(c riffChunk) bool {
+		return c.fourcc == "XMP "
+	})
+	if at < 0 {
+		at = len(chunks)
+	}
+	chunks = slices.Insert(chunks, at, riffChunk{fourcc: "EXIF", data: exif})
+	return joinWEBP(chunks), nil
+}
+
+// jpegSegment is a marker segment before the start of scan.
+type jpegSegment struct {
+	marker byte
+	data   []byte
+}
+
+// splitJPEG returns the segments before the start of scan and the remaining
+// bytes, beginning with the SOS marker.
+func splitJPEG(data []byte) ([]jpegSegment, []byte, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, nil, &CorruptError{Offset: 0, Err: errors.New("missing jpeg SOI marker")}
+	}
+	var segs []jpegSegment
+	i := 2
+	for i < len(data) {
+		s, next, err := readJPEGSegment(data, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case s.marker == 0xda:
+			return segs, data[i:], nil
+		case !s.standalone():
+			segs = append(segs, s)
+		}
+		i = next
+	}
+	return nil, nil, &CorruptError{Offset: int64(i), Err: errors.New("missing jpeg SOS marker")}
+}
+
+var errShortSegment = errors.New("short jpeg segment")
+
+// readJPEGSegment reads the marker at data[i] and the segment that follows
+// it, returning the offset of the next marker. Fill bytes are returned as
+// segments with the marker 0xff.
+func readJPEGSegment(data []byte, i int) (jpegSegment, int, error) {
+	if i+2 > len(data) || data[i] != 0xff {
+		return jpegSegment{}, 0, &CorruptError{Offset: int64(i), Err: errors.New("expected jpeg marker")}
+	}
+	s := jpegSegment{marker: data[i+1]}
+	if s.marker == 0xff {
+		return s, i + 1, nil
+	}
+	i += 2
+	if s.standalone() {
+		return s, i, nil
+	}
+	if i+2 > len(data) {
+		return jpegSegment{}, 0, &CorruptError{Offset: int64(i), Err: errShortSegment}
+	}
+	n := int(binary.BigEndian.Uint16(data[i:]))
+	if n < 2 || i+n > len(data) {
+		return jpegSegment{}, 0, &CorruptError{Offset: int64(i), Err: errors.New("bad jpeg segment length")}
+	}
+	s.data = data[i+2 : i+n]
+	return s, i + n, nil
+}
+
+// standalone reports whether the marker has no segment: fill bytes, SOI,
+// EOI, TEM and the restart markers.
+func (s jpegSegment) standalone() bool {
+	m := s.marker
+	return m == 0xff || m == 0x01 || m >= 0xd0 && m <= 0xd9
+}
+
+func joinJPEG(segs []jpegSegment, scan []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xff, 0xd8})
+	for _, s := range segs {
+		b.Write([]byte{0xff, s.marker})
+		binary.Write(&b, binary.BigEndian, uint16(len(s.data)+2))
+		b.Write(s.data)
+	}
+	b.Write(scan)
+	return b.Bytes()
+}
+
+func isJPEGXMP(s jpegSegment) bool {
+	return s.marker == 0xe1 && bytes.HasPrefix(s.data, []byte(jpegXMPHeader))
+}
+
+// embedJPEGXMP replaces the XMP APP1 segment, placing it after the JFIF and
+// EXIF segments.
+func embedJPEGXMP(data, packet []byte) ([]byte, error) {
+	segs, scan, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	payload := append([]byte(jpegXMPHeader), packet...)
+	if len(payload)+2 > 0xffff {
+		return nil, errXMPTooLarge
+	}
+	var out []jpegSegment
+	at := 0
+	for _, s := range segs {
+		if isJPEGXMP(s) {
+			continue
+		}
+		out = append(out, s)
+		if len(out) == at+1 && (s.marker == 0xe0 || s.marker == 0xe1) {
+			at++
+		}
+	}
+	out = append(out[:at], append([]jpegSegment{{marker: 0xe1, data: payload}}, out[at:]...)...)
+	return joinJPEG(out, scan), nil
+}
+
+// pngChunk is a chunk of a PNG file.
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+var pngSig = []byte("\x89PNG\r\n\x1a\n")
+
+func splitPNG(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, pngSig) {
+		return nil, &CorruptError{Offset: 0, Err: errors.New("missing png signature")}
+	}
+	var chunks []pngChunk
+	i := len(pngSig)
+	for i+12 <= len(data) {
+		n := int(binary.BigEndian.Uint32(data[i:]))
+		if n < 0 || i+12+n > len(data) {
+			return nil, &CorruptError{Offset: int64(i), Err: errors.New("bad png chunk length")}
+		}
+		chunks = append(chunks, pngChunk{typ: string(data[i+4 : i+8]), data: data[i+8 : i+8+n]})
+		i += 12 + n
+	}
+	return chunks, nil
+}
+
+func joinPNG(chunks []pngChunk) []byte {
+	var b bytes.Buffer
+	b.Write(pngSig)
+	for _, c := range chunks {
+		binary.Write(&b, binary.BigEndian, uint32(len(c.data)))
+		crc := crc32.NewIEEE()
+		crc.Write([]byte(c.typ))
+		crc.Write(c.data)
+		b.WriteString(c.typ)
+		b.Write(c.data)
+		binary.Write(&b, binary.BigEndian, crc.Sum32())
+	}
+	return b.Bytes()
+}
+
+func isPNGXMP(c pngChunk) bool {
+	return c.typ == "iTXt" && bytes.HasPrefix(c.data, []byte(pngXMPKeyword+"\x00"))
+}
+
+// embedPNGXMP replaces the XMP iTXt chunk, placing it after IHDR.
+func embedPNGXMP(data, packet []byte) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+	// keyword, null, uncompressed, method, empty language and translated
+	// keyword
+	itxt := append([]byte(pngXMPKeyword+"\x00\x00\x00\x00\x00"), packet...)
+	var out []pngChunk
+	for _, c := range chunks {
+		if isPNGXMP(c) {
+			continue
+		}
+		out = append(out, c)
+		if c.typ == "IHDR" {
+			out = append(out, pngChunk{typ: "iTXt", data: itxt})
+		}
+	}
+	return joinPNG(out), nil
+}
+
+// pngTextKeyword returns the keyword of a tEXt, zTXt or iTXt chunk.
+func pngTextKeyword(c pngChunk) string {
+	switch c.typ {
+	case "tEXt", "zTXt", "iTXt":
+		kw, _, _ := bytes.Cut(c.data, []byte{0})
+		return string(kw)
+	}
+	return ""
+}
+
+// embedPNGText replaces the text chunks with the given keywords, writing
+// Latin-1 values as tEXt and others as iTXt. Empty values are removed.
+func embedPNGText(data []byte, text map[string]string) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+	chunks = slices.DeleteFunc(chunks, func(c pngChunk) bool {
+		_, ok := text[pngTextKeyword(c)]
+		return ok
+	})
+	at := slices.IndexFunc(chunks, func(c pngChunk) bool {
+		return c.typ == "IHDR"
+	}) + 1
+	if at == 0 {
+		return nil, &CorruptError{Offset: 8, Err: errors.New("missing png IHDR chunk")}
+	}
+	var kws []string
+	for kw, v := range text {
+		if v != "" {
+			kws = append(kws, kw)
+		}
+	}
+	slices.Sort(kws)
+	var added []pngChunk
+	for _, kw := range kws {
+		v := text[kw]
+		if latin1, ok := toLatin1(v); ok {
+			added = append(added, pngChunk{typ: "tEXt", data: append([]byte(kw+"\x00"), latin1...)})
+			continue
+		}
+		added = append(added, pngChunk{typ: "iTXt", data: append([]byte(kw+"\x00\x00\x00\x00\x00"), v...)})
+	}
+	chunks = slices.Insert(chunks, at, added...)
+	return joinPNG(chunks), nil
+}
+
+func toLatin1(s string) ([]byte, bool) {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return nil, false
+		}
+		b = append(b, byte(r))
+	}
+	return b, true
+}
+
+// pngITXtText returns the text of an iTXt chunk, inflating it if needed.
+func pngITXtText(data []byte) ([]byte, error) {
+	kw, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok || len(kw) == 0 || len(rest) < 2 {
+		return nil, &CorruptError{Offset: -1, Err: errors.New("bad png iTXt chunk")}
+	}
+	compressed := rest[0] == 1
+	// skip the language tag and translated keyword
+	rest = rest[2:]
+	for range 2 {
+		_, rest, ok = bytes.Cut(rest, []byte{0})
+		if !ok {
+			return nil, &CorruptError{Offset: -1, Err: errors.New("bad png iTXt chunk")}
+		}
+	}
+	if !compressed {
+		return rest, nil
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(rest))
+	if err != nil {
+		return nil, &CorruptError{Offset: -1, Err: err}
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// riffChunk is a chunk of a WEBP file.
+type riffChunk struct {
+	fourcc string
+	data   []byte
+}
+
+func splitWEBP(data []byte) ([]riffChunk, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, &CorruptError{Offset: 0, Err: errors.New("missing webp header")}
+	}
+	var chunks []riffChunk
+	i := 12
+	for i+8 <= len(data) {
+		n := int(binary.LittleEndian.Uint32(data[i+4:]))
+		if n < 0 || i+8+n > len(data) {
+			return nil, &CorruptError{Offset: int64(i), Err: errors.New("bad webp chunk length")}
+		}
+		chunks = append(chunks, riffChunk{fourcc: string(data[i : i+4]), data: data[i+8 : i+8+n]})
+		i += 8 + n + n&1
+	}
+	return chunks, nil
+}
+
+func joinWEBP(chunks []riffChunk) []byte {
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	for _, c := range chunks {
+		body.WriteString(c.fourcc)
+		binary.Write(&body, binary.LittleEndian, uint32(len(c.data)))
+		body.Write(c.data)
+		if len(c.data)&1 == 1 {
+			body.WriteByte(0)
+		}
+	}
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(body.Len()))
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+const (
+	vp8xXMPFlag   = 0x04
+	vp8xEXIFFlag  = 0x08
+	vp8xAlphaFlag = 0x10
+)
+
+// embedWEBPXMP replaces the "XMP " chunk. A simple WEBP is converted to the
+// extended format, which is required for metadata.
+func embedWEBPXMP(data, packet []byte) ([]byte, error) {
+	chunks, err := splitWEBP(data)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err = withVP8XFlag(chunks, vp8xXMPFlag)
+	if err != nil {
+		return nil, err
+	}
+	out := slices.DeleteFunc(chunks, func(c riffChunk) bool {
+		return c.fourcc == "XMP "
+	})
+	out = append(out, riffChunk{fourcc: "XMP ", data: packet})
+	return joinWEBP(out), nil
+}
+
+// withVP8XFlag sets a feature flag in the extended header, adding one if
+// needed.
+func withVP8XFlag(chunks []riffChunk, flag byte) ([]riffChunk, error) {
+	if len(chunks) == 0 {
+		return nil, &CorruptError{Offset: 12, Err: errors.New("empty webp")}
+	}
+	if chunks[0].fourcc != "VP8X" {
+		vp8x, err := newVP8X(chunks[0])
+		if err != nil {
+			return nil, err
+		}
+		chunks = append([]riffChunk{vp8x}, chunks...)
+	}
+	vp8x := slices.Clone(chunks[0].data)
+	vp8x[0] |= flag
+	chunks[0].data = vp8x
+	return chunks, nil
+}
+
+// newVP8X makes an extended header for a simple lossy or lossless WEBP.
+func newVP8X(c riffChunk) (riffChunk, error) {
+	var (
+		w, h  int
+		flags byte
+	)
+	switch c.fourcc {
+	case "VP8L":
+		if len(c.data) < 5 || c.data[0] != 0x2f {
+			return c, &CorruptError{Offset: 20, Err: errors.New("bad VP8L header")}
+		}
+		bits := binary.LittleEndian.Uint32(c.data[1:])
+		w = int(bits&0x3fff) + 1
+		h = int(bits>>14&0x3fff) + 1
+		if bits>>28&1 == 1 {
+			flags |= vp8xAlphaFlag
+		}
+	case "VP8 ":
+		if len(c.data) < 10 {
+			return c, &CorruptError{Offset: 20, Err: errors.New("bad VP8 header")}
+		}
+		w = int(binary.LittleEndian.Uint16(c.data[6:]) & 0x3fff)
+		h = int(binary.LittleEndian.Uint16(c.data[8:]) & 0x3fff)
+	default:
+		return c, &CorruptError{Offset: 12, Err: errors.New("unknown webp chunk " + c.fourcc)}
+	}
+	d := make([]byte, 10)
+	d[0] = flags
+	putUint24(d[4:], uint32(w-1))
+	putUint24(d[7:], uint32(h-1))
+	return riffChunk{fourcc: "VP8X", data: d}, nil
+}
+
+func putUint24(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// tiffEntry is an IFD entry with its value or value offset left as is.
+type tiffEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value [4]byte
+}
+
+// tiffValue is a BYTE or ASCII value written to IFD0. An empty value
+// removes the entry.
+type tiffValue struct {
+	tag  uint16
+	typ  uint16
+	data []byte
+}
+
+const (
+	tiffByte  = 1
+	tiffASCII = 2
+)
+
+// tiffASCIIValue makes a NUL terminated ASCII entry, or an empty one that
+// removes the entry.
+func tiffASCIIValue(tag uint16, s string) tiffValue {
+	v := tiffValue{tag: tag, typ: tiffASCII}
+	if s != "" {
+		v.data = append([]byte(s), 0)
+	}
+	return v
+}
+
+// embedTIFFXMP sets the XMLPacket entry of IFD0.
+func embedTIFFXMP(data, packet []byte) ([]byte, error) {
+	return embedTIFFValues(data, []tiffValue{{tag: tiffXMPTag, typ: tiffByte, data: packet}})
+}
+
+// embedTIFFValues writes the values and a copy of IFD0 with their entries
+// to the end of the file and points the header at the new IFD. The old IFD
+// and the replaced values are dropped if they are at the end of the file, as
+// they are after an earlier save, and otherwise left in place, unreferenced.
+func embedTIFFValues(data []byte, vals []tiffValue) ([]byte, error) {
+	if len(data) < 8 {
+		return nil, &CorruptError{Offset: 0, Err: errors.New("missing tiff header")}
+	}
+	var bo binary.ByteOrder
+	switch string(data[0:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return nil, &CorruptError{Offset: 0, Err: errors.New("bad tiff byte order")}
+	}
+	if bo.Uint16(data[2:]) != 42 {
+		return nil, ErrUnsupportedFormat
+	}
+	ifd := int(bo.Uint32(data[4:]))
+	if ifd+2 > len(data) {
+		return nil, &CorruptError{Offset: 4, Err: errors.New("bad tiff ifd offset")}
+	}
+	n := int(bo.Uint16(data[ifd:]))
+	end := ifd + 2 + n*12
+	if end+4 > len(data) {
+		return nil, &CorruptError{Offset: int64(ifd), Err: errors.New("bad tiff ifd")}
+	}
+	next := bo.Uint32(data[end:])
+
+	// spans are the bytes only used by IFD0 and the values being replaced
+	spans := [][2]int{{ifd, end + 4}}
+	for i := range n {
+		e := data[ifd+2+i*12:]
+		tag := bo.Uint16(e)
+		if !slices.ContainsFunc(vals, func(v tiffValue) bool { return v.tag == tag }) {
+			continue
+		}
+		size := int(bo.Uint32(e[4:])) * tiffTypeSize(bo.Uint16(e[2:]))
+		if size > 4 {
+			off := int(bo.Uint32(e[8:]))
+			spans = append(spans, [2]int{off, off + size})
+		}
+	}
+	tail := len(data)
+	for found := true; found; {
+		found = false
+		for _, s := range spans {
+			padded := s[1]&1 == 1 && s[1]+1 == tail
+			if s[0] >= 8 && s[0] < tail && (s[1] == tail || padded) {
+				tail = s[0]
+				found = true
+			}
+		}
+	}
+
+	out := bytes.NewBuffer(slices.Clone(data[:tail]))
+	pad := func() {
+		if out.Len()&1 == 1 {
+			out.WriteByte(0)
+		}
+	}
+
+	entries := make([]tiffEntry, 0, n+len(vals))
+	for i := range n {
+		e := data[ifd+2+i*12:]
+		entry := tiffEntry{tag: bo.Uint16(e), typ: bo.Uint16(e[2:]), count: bo.Uint32(e[4:])}
+		copy(entry.value[:], e[8:12])
+		if slices.ContainsFunc(vals, func(v tiffValue) bool { return v.tag == entry.tag }) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	for _, v := range vals {
+		if len(v.data) == 0 {
+			continue
+		}
+		entry := tiffEntry{tag: v.tag, typ: v.typ, count: uint32(len(v.data))}
+		if len(v.data) <= 4 {
+			copy(entry.value[:], v.data)
+		} else {
+			pad()
+			bo.PutUint32(entry.value[:], uint32(out.Len()))
+			out.Write(v.data)
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b tiffEntry) int {
+		return int(a.tag) - int(b.tag)
+	})
+	pad()
+
+	newIFD := out.Len()
+	binary.Write(out, bo, uint16(len(entries)))
+	for _, e := range entries {
+		binary.Write(out, bo, e.tag)
+		binary.Write(out, bo, e.typ)
+		binary.Write(out, bo, e.count)
+		out.Write(e.value[:])
+	}
+	binary.Write(out, bo, next)
+
+	b := out.Bytes()
+	bo.PutUint32(b[4:], uint32(newIFD))
+	return b, nil
+}
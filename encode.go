@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/HugoSmits86/nativewebp"
+	"github.com/evanoberholster/imagemeta/xmp"
 	"github.com/hhrutter/tiff"
 	"github.com/sunshineplan/pdf"
 	"golang.org/x/image/bmp"
@@ -45,6 +46,12 @@ type Encoder struct {
 	webpDisposal          uint
 	webpDuration          uint
 	isAnimated            bool
+	cbzPageFormat         Format
+	comicInfo             *xmp.DublinCore
+	strip                 *StripPolicy
+	// metaOpts counts the options that only affect metadata, so saving with
+	// nothing else can skip re-encoding.
+	metaOpts int
 }
 
 // Save saves image according to the encoder
@@ -69,9 +76,43 @@ func SaveAll(output string, images []image.Image, opts ...EncodeOption) error {
 	return NewEncoder(imgFmt, opts...).SaveAll(output, images)
 }
 
+// ConvertAll decodes every page or frame of input and saves them to output, so
+// that, for example, a PDF becomes a CBZ in a single call. The metadata of
+// input is kept as the ComicInfo.xml of a CBZ.
+func ConvertAll(input, output string, opts ...EncodeOption) error {
+	to, err := FormatFromFilename(output)
+	if err != nil {
+		return err
+	}
+	img, err := New(input)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	pages, err := img.Fmt.DecodeAll(f)
+	if err != nil {
+		return err
+	}
+	if to == CBZ && img.Fmt.hasMeta() {
+		err = img.ReadMeta()
+		if err != nil {
+			return err
+		}
+		opts = img.comicInfo(to, opts)
+	}
+	return NewEncoder(to, opts...).SaveAll(output, pages)
+}
+
 // NewEncoder initializes an encoder.
 func NewEncoder(format Format, opts ...EncodeOption) *Encoder {
-	enc := defaultEncodeConfig
+	def := *defaultEncodeConfig
+	enc := &def
+	enc.gifAnimation = &gif.GIF{}
+	enc.webpAnimation = &nativewebp.Animation{}
 	enc.Format = format
 	for _, option := range opts {
 		option(enc)
@@ -82,10 +123,15 @@ func NewEncoder(format Format, opts ...EncodeOption) *Encoder {
 // Save saves image according to the encoder
 // https://github.com/sunshineplan/imgconv
 func (enc *Encoder) Save(output string, base image.Image) error {
+	return enc.SaveFS(osFS, output, base)
+}
+
+// SaveFS saves image to fsys according to the encoder.
+func (enc *Encoder) SaveFS(fsys WriteFS, output string, base image.Image) error {
 	if !HasExt(output) {
 		output = output + enc.Format.String()
 	}
-	f, err := os.Create(output)
+	f, err := fsys.Create(output)
 	if err != nil {
 		return err
 	}
@@ -95,6 +141,16 @@ func (enc *Encoder) Save(output string, base image.Image) error {
 
 // SaveAll saves images according to the encoder
 func (enc *Encoder) SaveAll(output string, images []image.Image) error {
+	return enc.SaveAllFS(osFS, output, images)
+}
+
+// SaveAllFS saves images to fsys according to the encoder. A CBZ or PDF is
+// saved as a single file with a page for each image.
+func (enc *Encoder) SaveAllFS(fsys WriteFS, output string, images []image.Image) error {
+	if enc.Format.multiPage() && len(images) > 0 {
+		enc.pages = images[1:]
+		return enc.SaveFS(fsys, output, images[0])
+	}
 	enc.batch = true
 	ext := filepath.Ext(output)
 	if ext == "" {
@@ -106,7 +162,7 @@ func (enc *Encoder) SaveAll(output string, images []image.Image) error {
 	if enc.batch {
 		for i, img := range images {
 			n := fmt.Sprintf(base+enc.padding+ext, i)
-			f, err := os.Create(filepath.Join(dir, n))
+			f, err := fsys.Create(filepath.Join(dir, n))
 			if err != nil {
 				return err
 			}
@@ -175,7 +231,7 @@ func (enc *Encoder) AnimatedGIF(output string, images []string) error {
 }
 
 // Encode writes the image img to w in the specified format (JPEG, PNG, GIF,
-// TIFF, BMP, PDF, WEBP, HTML, or BASE64).
+// TIFF, BMP, PDF, WEBP, CBZ, HTML, or BASE64).
 func (enc *Encoder) Encode(w io.Writer, img image.Image) error {
 	if enc.background != nil {
 		i := image.NewNRGBA(img.Bounds())
@@ -236,6 +292,11 @@ func (enc *Encoder) encode(w io.Writer, img image.Image) error {
 		pages = append(pages, enc.pages...)
 		return pdf.Encode(w, pages, &pdf.Options{Quality: enc.Quality})
 
+	case CBZ:
+		pages := []image.Image{img}
+		pages = append(pages, enc.pages...)
+		return enc.encodeCBZ(w, pages)
+
 	case GIF:
 		return gif.Encode(w, img, &gif.Options{
 			NumColors: enc.gifNumColors,
@@ -251,7 +312,7 @@ func (enc *Encoder) encode(w io.Writer, img image.Image) error {
 		return nativewebp.Encode(w, img, webpOpts)
 	}
 
-	return image.ErrFormat
+	return &FormatError{Format: enc.Format, Op: "encode", Err: ErrUnsupportedFormat}
 }
 
 func (enc *Encoder) animatedWebp(w io.Writer) error {
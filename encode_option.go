@@ -9,6 +9,7 @@ import (
 	"mime"
 
 	"github.com/HugoSmits86/nativewebp"
+	"github.com/evanoberholster/imagemeta/xmp"
 	"github.com/spf13/cast"
 )
 
@@ -203,6 +204,32 @@ func PDFPages(pages []image.Image) EncodeOption {
 	}
 }
 
+// CBZPageFormat returns an EncodeOption that sets the format of the pages in a
+// CBZ. Default is JPEG.
+func CBZPageFormat(f Format) EncodeOption {
+	return func(c *Encoder) {
+		c.cbzPageFormat = f
+	}
+}
+
+// ComicInfo returns an EncodeOption that adds a ComicInfo.xml with the title,
+// description, creator and subject of dc to a CBZ.
+func ComicInfo(dc xmp.DublinCore) EncodeOption {
+	return func(c *Encoder) {
+		c.comicInfo = &dc
+	}
+}
+
+// StripMeta returns an EncodeOption that removes metadata according to
+// policy. Saving an Img in its own format with only this option doesn't
+// re-encode the pixels.
+func StripMeta(policy StripPolicy) EncodeOption {
+	return func(c *Encoder) {
+		c.strip = &policy
+		c.metaOpts++
+	}
+}
+
 // Base64 returns an EncodeOption that encodes the format to Base64.
 func Base64(outFmt Format) EncodeOption {
 	return func(c *Encoder) {
@@ -218,4 +245,5 @@ func init() {
 	for _, ext := range []string{".b64", "uue"} {
 		mime.AddExtensionType(ext, "text/plain")
 	}
+	mime.AddExtensionType(".cbz", "application/vnd.comicbook+zip")
 }
@@ -0,0 +1,151 @@
+package img
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/bep/imagemeta"
+	"github.com/hhrutter/tiff"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for file extensions and formats that
+	// can't be decoded or encoded. It wraps image.ErrFormat.
+	ErrUnsupportedFormat = fmt.Errorf("unsupported format: %w", image.ErrFormat)
+
+	// ErrAnimatedNotSupported is returned when decoding an animated image as a
+	// still image. Use Format.DecodeAnimatedWebP instead.
+	ErrAnimatedNotSupported = errors.New("animated image not supported")
+
+	// ErrNoMetadata is returned when an image has none of the supported
+	// metadata fields.
+	ErrNoMetadata = errors.New("no metadata")
+
+	// ErrNotTagField is returned when marshaling Tags to an ExifField that
+	// doesn't hold keywords.
+	ErrNotTagField = errors.New("not a tag field")
+
+	// ErrNoFileName is returned when saving an Img that wasn't opened from a
+	// file. Use SaveAs or WriteTo instead.
+	ErrNoFileName = errors.New("img has no file name")
+
+	// ErrMultiPage is returned when saving a PDF or CBZ read from a file in
+	// its own format would re-encode it, keeping only the first page.
+	ErrMultiPage = errors.New("can't re-encode the pages of a multi-page image")
+
+	// ErrCorrupt is matched by a *CorruptError.
+	ErrCorrupt = errors.New("corrupt image")
+
+	// ErrLimitExceeded is returned, wrapped in a *LimitError, when an image is
+	// larger than a limit set with MaxPixels, MaxWidth, MaxHeight, MaxFrames
+	// or MaxBytes.
+	ErrLimitExceeded = errors.New("decode limit exceeded")
+)
+
+// FormatError records the format and operation that caused an error.
+type FormatError struct {
+	Format Format
+	Op     string
+	Err    error
+}
+
+func (e *FormatError) Error() string {
+	return strings.TrimPrefix(e.Format.String(), ".") + " " + e.Op + ": " + e.Err.Error()
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// CorruptError is returned when the image data is malformed. Offset is the
+// number of bytes read when the error was found, or -1 if unknown.
+type CorruptError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *CorruptError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s: %v", ErrCorrupt, e.Err)
+	}
+	return fmt.Sprintf("%s at offset %d: %v", ErrCorrupt, e.Offset, e.Err)
+}
+
+func (e *CorruptError) Is(target error) bool {
+	return target == ErrCorrupt
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+// LimitError records which decode limit was exceeded.
+type LimitError struct {
+	Limit string
+	Max   int64
+	Got   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s %d is greater than %d", ErrLimitExceeded, e.Limit, e.Got, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// formatErr wraps err in a *FormatError, marking malformed data as a
+// *CorruptError at offset.
+func formatErr(f Format, op string, offset int64, err error) error {
+	if isCorrupt(err) {
+		err = &CorruptError{Offset: offset, Err: err}
+	}
+	return &FormatError{Format: f, Op: op, Err: err}
+}
+
+func isCorrupt(err error) bool {
+	var (
+		pngErr  png.FormatError
+		jpegErr jpeg.FormatError
+		tiffErr tiff.FormatError
+	)
+	switch {
+	case errors.Is(err, ErrCorrupt),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &pngErr),
+		errors.As(err, &jpegErr),
+		errors.As(err, &tiffErr),
+		imagemeta.IsInvalidFormat(err):
+		return true
+	}
+	return false
+}
+
+// countingReader counts the bytes read so errors can report an offset.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written for io.WriterTo.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,54 @@
+package img
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestFormatErrors(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := FormatFromExtension(".ppp")
+	c.Assert(err, qt.ErrorIs, ErrUnsupportedFormat)
+	c.Assert(err, qt.ErrorIs, image.ErrFormat)
+
+	_, err = PNG.Decode(bytes.NewReader([]byte("\x89PNG\r\n\x1a\nnot a png")))
+	var ferr *FormatError
+	c.Assert(errors.As(err, &ferr), qt.IsTrue)
+	c.Assert(ferr.Format, qt.Equals, PNG)
+	c.Assert(ferr.Op, qt.Equals, "decode")
+	c.Assert(err, qt.ErrorIs, ErrCorrupt)
+
+	var cerr *CorruptError
+	c.Assert(errors.As(err, &cerr), qt.IsTrue)
+	c.Assert(cerr.Offset > 0, qt.IsTrue)
+
+	err = HTML.Encode(&bytes.Buffer{}, image.NewGray(image.Rect(0, 0, 1, 1)))
+	c.Assert(err, qt.ErrorIs, ErrUnsupportedFormat)
+}
+
+func TestAnimatedWEBPError(t *testing.T) {
+	c := qt.New(t)
+	frames := []image.Image{
+		image.NewNRGBA(image.Rect(0, 0, 8, 8)),
+		image.NewNRGBA(image.Rect(0, 0, 8, 8)),
+	}
+	var buf bytes.Buffer
+	err := WEBP.Encode(&buf, frames[0],
+		WEBPAnimationFrames(frames),
+		WEBPAnimationDurations([]int{10, 10}),
+		WEBPAnimationDisposals([]int{0, 0}),
+	)
+	c.Assert(err, qt.IsNil)
+
+	_, err = WEBP.Decode(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, qt.ErrorIs, ErrAnimatedNotSupported)
+
+	n, err := countWEBPFrames(buf.Bytes())
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 2)
+}
@@ -112,7 +112,7 @@ func (f ExifField) Sep() string {
 	switch f {
 	case HierarchicalSubject, CatalogSets:
 		return barSep
-	case LastKeywordXMP:
+	case LastKeywordXMP, TagsList:
 		return slashSep
 	default:
 		return ""
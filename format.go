@@ -1,10 +1,15 @@
 package img
 
 import (
+	"bufio"
+	"bytes"
 	"encoding"
 	"fmt"
 	"image"
+	"image/color"
+	"image/gif"
 	"io"
+	"math"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -14,10 +19,11 @@ import (
 	"github.com/evanoberholster/imagemeta/imagetype"
 	"github.com/gen2brain/webp"
 	"github.com/hhrutter/tiff"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/samber/lo"
-	"github.com/sunshineplan/imgconv"
-	"github.com/sunshineplan/pdf"
 	"golang.org/x/image/bmp"
+	xwebp "golang.org/x/image/webp"
 )
 
 var (
@@ -40,6 +46,7 @@ const (
 	HTML
 	BASE64
 	URL
+	CBZ
 )
 
 var formatExts = [][]string{
@@ -52,6 +59,9 @@ var formatExts = [][]string{
 	{".webp"},
 	{".html"},
 	{".b64", ".uue"},
+	// URL has no extension
+	{},
+	{".cbz"},
 }
 
 func (f Format) String() (format string) {
@@ -79,40 +89,164 @@ func (f Format) DecodeAnimatedWebP(r io.Reader) (*webp.WEBP, error) {
 	return webp.DecodeAll(r)
 }
 
-func (f Format) Decode(r io.Reader) (image.Image, error) {
+// DecodeAll decodes every page or frame from r: the pages of a CBZ or PDF, the
+// frames of an animated GIF or WEBP, or the single image of any other format.
+// DecodeOptions such as MaxFrames are checked before anything is decoded, and
+// the pixel limits are also checked for each page of a CBZ or PDF.
+func (f Format) DecodeAll(r io.Reader, opts ...DecodeOption) ([]image.Image, error) {
+	dec := NewDecoder(r, opts...)
+	if dec.hasLimits() {
+		var err error
+		r, err = dec.checkLimits(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cr := &countingReader{r: r}
+	var (
+		imgs []image.Image
+		err  error
+	)
 	switch f {
+	case CBZ:
+		imgs, err = decodeCBZ(cr, true, dec.pageOptions()...)
 	case PDF:
-		img, err := pdf.Decode(r)
-		if err != nil {
-			return nil, fmt.Errorf("pdf.Decode %w", err)
+		imgs, err = decodePDF(cr, true, dec.pageOptions()...)
+	case GIF:
+		var g *gif.GIF
+		g, err = gif.DecodeAll(cr)
+		if err == nil {
+			for _, frame := range g.Image {
+				imgs = append(imgs, frame)
+			}
 		}
-		return img, nil
 	case WEBP:
-		img, err := webp.Decode(r)
-		if err != nil {
-			return nil, fmt.Errorf("this webp is probably animated, use DecodeAnimatedWebP %w", err)
+		var w *webp.WEBP
+		w, err = webp.DecodeAll(cr)
+		if err == nil {
+			imgs = w.Image
 		}
-		return img, nil
-	case TIFF:
-		img, err := tiff.Decode(r)
+	default:
+		var img image.Image
+		img, err = f.decode(cr)
 		if err != nil {
-			return img, fmt.Errorf("tiff.Decode %w", err)
+			return nil, err
 		}
-		return img, nil
-	case BMP:
-		img, err := bmp.Decode(r)
-		if err != nil {
-			return nil, fmt.Errorf("bmp.Decode %w", err)
+		imgs = []image.Image{img}
+	}
+	if err != nil {
+		return nil, formatErr(f, "decode all", cr.n, err)
+	}
+	return imgs, nil
+}
+
+// Decode decodes an image from r. DecodeOptions such as MaxPixels are
+// applied before the image is decoded.
+func (f Format) Decode(r io.Reader, opts ...DecodeOption) (image.Image, error) {
+	return NewDecoder(r, opts...).Decode(f)
+}
+
+func (f Format) decode(r io.Reader) (image.Image, error) {
+	cr := &countingReader{r: r}
+	var (
+		img image.Image
+		err error
+	)
+	switch f {
+	case CBZ:
+		var pages []image.Image
+		pages, err = decodeCBZ(cr, false)
+		if err == nil {
+			img = pages[0]
+		}
+	case PDF:
+		var pages []image.Image
+		pages, err = decodePDF(cr, false)
+		if err == nil {
+			img = pages[0]
 		}
-		return img, nil
+	case WEBP:
+		br := bufio.NewReader(cr)
+		if isAnimatedWEBP(br) {
+			return nil, &FormatError{Format: f, Op: "decode", Err: ErrAnimatedNotSupported}
+		}
+		img, err = webp.Decode(br)
+	case TIFF:
+		img, err = tiff.Decode(cr)
+	case BMP:
+		img, err = bmp.Decode(cr)
+	case PNG, JPEG, GIF:
+		img, _, err = image.Decode(cr)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, formatErr(f, "decode", cr.n, err)
+	}
+	return img, nil
+}
+
+// isAnimatedWEBP checks the animation flag of an extended WEBP header.
+func isAnimatedWEBP(br *bufio.Reader) bool {
+	hdr, err := br.Peek(21)
+	if err != nil {
+		return false
+	}
+	return string(hdr[12:16]) == "VP8X" && hdr[20]&0x02 != 0
+}
+
+// DecodeConfig returns the color model and dimensions of an image without
+// decoding it. The size of an animated WEBP is its canvas, the size of a PDF
+// is the media box of the first page and the size of a CBZ is its first page.
+func (f Format) DecodeConfig(r io.Reader) (image.Config, error) {
+	cr := &countingReader{r: r}
+	cfg, err := f.decodeConfig(cr)
+	if err != nil {
+		return cfg, formatErr(f, "decode config", cr.n, err)
+	}
+	return cfg, nil
+}
+
+func (f Format) decodeConfig(r io.Reader) (image.Config, error) {
+	switch f {
+	case CBZ:
+		return decodeCBZConfig(r)
+	case PDF:
+		return pdfConfig(r)
+	case WEBP:
+		return xwebp.DecodeConfig(r)
+	case TIFF:
+		return tiff.DecodeConfig(r)
+	case BMP:
+		return bmp.DecodeConfig(r)
 	case PNG, JPEG, GIF:
-		img, _, err := image.Decode(r)
+		cfg, _, err := image.DecodeConfig(r)
+		return cfg, err
+	}
+	return image.Config{}, ErrUnsupportedFormat
+}
+
+func pdfConfig(r io.Reader) (image.Config, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(r)
 		if err != nil {
-			return nil, fmt.Errorf("error from image.Decode %w", err)
+			return image.Config{}, err
 		}
-		return img, nil
+		rs = bytes.NewReader(b)
 	}
-	return nil, fmt.Errorf("error from f.Decode %w", image.ErrFormat)
+	dims, err := api.PageDims(rs, model.NewDefaultConfiguration())
+	if err != nil {
+		return image.Config{}, err
+	}
+	if len(dims) == 0 {
+		return image.Config{}, ErrCorrupt
+	}
+	return image.Config{
+		ColorModel: color.RGBAModel,
+		Width:      int(math.Round(dims[0].Width)),
+		Height:     int(math.Round(dims[0].Height)),
+	}, nil
 }
 
 func (f Format) metaFmt() imagemeta.ImageFormat {
@@ -130,16 +264,27 @@ func (f Format) metaFmt() imagemeta.ImageFormat {
 	}
 }
 
-func (f Format) convFmt() imgconv.Format {
-	return imgconv.Format(int(f))
+// hasMeta reports whether metadata can be read from the format.
+func (f Format) hasMeta() bool {
+	return f == CBZ || f.metaFmt() != imagemeta.ImageFormatAuto
+}
+
+// multiPage reports whether the format holds pages, of which Decode only
+// returns the first.
+func (f Format) multiPage() bool {
+	return f == CBZ || f == PDF
 }
 
 func (f Format) ImageType() imagetype.ImageType {
 	return imagetype.FromString(f.String())
 }
 
-// MimeType returns the mimetype of the image format.
+// MimeType returns the mimetype of the image format. imagetype doesn't know
+// comic archives, so CBZ is given the usual unregistered type.
 func (f Format) MimeType() string {
+	if f == CBZ {
+		return "application/vnd.comicbook+zip"
+	}
 	return f.ImageType().String()
 }
 
@@ -158,7 +303,7 @@ func (f Format) MarshalText() ([]byte, error) {
 
 // FormatFromExtension parses image format from filename extension:
 // ".jpg" (or ".jpeg"), ".png", ".gif", ".tif" (or ".tiff"), ".bmp", ".pdf",
-// ".b64 (or ".uue") and ".webp" are supported.
+// ".cbz", ".b64 (or ".uue") and ".webp" are supported.
 func FormatFromExtension(ext string) (Format, error) {
 	ext = strings.ToLower(ext)
 	if !strings.HasPrefix(ext, ".") {
@@ -169,7 +314,31 @@ func FormatFromExtension(ext string) (Format, error) {
 			return Format(index), nil
 		}
 	}
-	return -1, image.ErrFormat
+	return -1, fmt.Errorf("%w %q", ErrUnsupportedFormat, ext)
+}
+
+// FormatFromBytes detects the image format from the magic number at the start
+// of b.
+func FormatFromBytes(b []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(b, []byte("\xff\xd8\xff")):
+		return JPEG, nil
+	case bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG, nil
+	case bytes.HasPrefix(b, []byte("GIF8")):
+		return GIF, nil
+	case bytes.HasPrefix(b, []byte("II*\x00")), bytes.HasPrefix(b, []byte("MM\x00*")):
+		return TIFF, nil
+	case bytes.HasPrefix(b, []byte("BM")):
+		return BMP, nil
+	case bytes.HasPrefix(b, []byte("%PDF")):
+		return PDF, nil
+	case len(b) >= 12 && string(b[0:4]) == "RIFF" && string(b[8:12]) == "WEBP":
+		return WEBP, nil
+	case bytes.HasPrefix(b, []byte("PK\x03\x04")):
+		return CBZ, nil
+	}
+	return -1, ErrUnsupportedFormat
 }
 
 func HasExt(name string) bool {
@@ -188,6 +357,13 @@ func IsValidFormat(name string) bool {
 	return slices.Contains(lo.Flatten(formatExts), filepath.Ext(strings.ToLower(name)))
 }
 
+// HasMeta reports whether name has the extension of a format that metadata
+// can be read from.
+func HasMeta(name string) bool {
+	f, err := FormatFromFilename(name)
+	return err == nil && f.hasMeta()
+}
+
 func FormatFromFilename(name string) (Format, error) {
 	return FormatFromExtension(filepath.Ext(name))
 }
@@ -1,6 +1,8 @@
 package img
 
 import (
+	"bytes"
+	"image"
 	"os"
 	"testing"
 )
@@ -18,6 +20,34 @@ func TestValidFormat(t *testing.T) {
 	}
 }
 
+func TestHasMeta(t *testing.T) {
+	for name, want := range map[string]bool{
+		"toot.JPG":  true,
+		"toot.webp": true,
+		"toot.cbz":  true,
+		"toot.gif":  false,
+		"toot.html": false,
+		"toot.b64":  false,
+		"toot":      false,
+	} {
+		if got := HasMeta(name); got != want {
+			t.Errorf("HasMeta(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMimeType(t *testing.T) {
+	for f, want := range map[Format]string{
+		JPEG: "image/jpeg",
+		PNG:  "image/png",
+		CBZ:  "application/vnd.comicbook+zip",
+	} {
+		if got := f.MimeType(); got != want {
+			t.Errorf("%v.MimeType() = %q, want %q", f, got, want)
+		}
+	}
+}
+
 func TestSaveFormat(t *testing.T) {
 	tstImg := `testdata/video-001.png`
 	img, err := open(tstImg)
@@ -117,3 +147,21 @@ func TestHTML(t *testing.T) {
 }
 
 const tstPngImgB64 = `iVBORw0KGgoAAAANSUhEUgAAAJYAAABnCAIAAABtrSwJAABv0UlEQVR4nFz9B7Rl2VkfiH87nXjzuy/Vq5y6Old3q4NSo9RqgRJ/gQhCSH/AxpoBBDYe4yXCeBZrYdJ4xjYWGATjYRmbJTAmCEkoSy211K3O1d1V3V256tXLN5+8w6y99zn33uKtp9ard+89Z5/9pd/3fb9vP/qrDQxYAQICCGEFUskCRAEIAyDAGABAAYAEpUBJwAgw0a8iAUroHyhFju+kkxwh/WYp9dsRIGU/x8vrYKJ/mL5UFECo+SUDRAFhhDDSty/0jfRNlX2z/p8Ueo0YVysB/RuQ5m3MvA2V3+UNEKhx+TOY1SqzGiXLX+oHMT/qp8QIlH7qnCHqAMpU0YSXJ+TsSBw+0nmdO8GjPMgRopAKlSvwCBABgup9UcqsBMrLYgxIlr/Xj+yYjTL3VRwwMnuWg0D62RECAeA4mGdSCrsbSAolhV4qT/XiCAFsdwnKp5OJvpoU5X31/gshwUgCQBEKlGCMJWHmmVX5Sf1uUi4FuBWpvq7dEKUULwqMzdvtRitASCEjBuaXD6hfts8slZTAXH1prTTI7KBQShjZK7PvqtIe87p+YFFJFGvh6ecxbyCsUjJV7SQynyLmdlgvHin9gHp/CSi7EULvtZLAJSCuxUswEEoQlki/2yzNLNBeXYLCSm/C9L9iuhL7vOb+5TPKcuVa8wCk+SVwUFRh8yO2CocR4kp/mZVIVWqV/XJr9q1a8EaJlZWIfjpk1KWSK9XSkuVdBQds5KIkUJdIKc2CFK40rlwuLncZ2V2WIHKpqh0vNd0oCMLAufkBlfdWUu8jtlcAhRCSxlwVKtdXbhoCK0r7WbBLrySkH8D8UsrSsu0/p3K0UudG9+3F9VJdQARRivUTSRBCIgHCqDMSQBAIpPS2EiAUWc0A+9xS30j7nsp69GVx6SEwLdUdqWon5cx1lVougUrARvPsRiH9CMrsj5r6m9KBmStjXLosI7pKL5HxWFIrnKokQrWiYXMNs7lSgrUe6/eUUAqVloFQKTwlSxUjtFyrNOu2pm0XpG1RloYy/cG6GiubylcrVdmHlY3IAFUaUFqA0XQglQ+0/29/X/kxjEu7xFPnS7TdiOq+YLYeY5xOSsdnHxoJs3cImPZXGBPtYRExhqifhWOKFQEoKj0w/kCq0h9gY+sz4Rm7hMpvWymUAiP6n3qjitK9mxuXEp1qXulOEAiupkEBFFS+ST8pMXqPVOVIeV46IMq0SGglXmuCpR6Wum4dFxJcKW7uJ0sHpY2BGt8FcyZoFkRI+XFpnlOHxjmhTr2r9i3aOpHIyqdQsly9/qcw1zcyRtNXrXhEKVclq0Xqx7Z6VGqMIuWuaavApQYoDMQaqNKLNFoidVzUkkalG5cSUyZJVvobXN4Qy8o9GNnMdNeovrVIq/EIl1pYqrvQP0vQ0VRLutqEyi2Vu6E3TVZCm/sqxamU3nM5daTGD0yNTFYbIYWauiYEMylqV4OUdXd2ici6Ncf4PVRqirVFglGRqxKGgDE1Ys221BW7L9ZLgAAhFar0DqauX5ZvK69cqYiVWXkvsz7rV62ToMZEpLThBGTpFZRXwyb+KO03lSLmhjroFUI/mFLVLpkVqQww1Wum5rvy8BKB3XP7vOViTKTACFl/OFUg63tkZQQ21ihqnkiWa5tGECnLa1JPRy+pgwFIZWxuDp3MR1/KfKSkktwYigF+ZcSy24Ks/ylhoTFQqS2PzSNMbSJC6qgAWimqGyjjJ63bMQqrAwkuvTxWer+kXt3Me0/hSRnnjLD/kTKqShmtoYhsJldMZpaKqncja2qlXoIkUgdvDQ01JtFSBK1kZgEKVZHcemgppF1W6S2MFPWzFzO/NEO5Uy2vbm9jVblvjtlDWgJDA8JtGEbE+u3K5yuLa4wWIYSElOXmmDBR6srsZkDtZgmDnayV2I0oYhOx7WpQ6c1LDar8JKqgoI1tFgeiKfwVJow75futQzZB1CDSMjiBUJVTMm/DFhxLhZGBajYFUTMVLkFyFeGlLJ+HGK0ycEPnJzKTVg5lLmHxFDG+S1Zbb6G/xu4YFdIgY2UhpcZZYHTMXAJNH8FuBTPXqLbSSrFEy6rcpWk6YV8qrLtG2j1IAoQQoR9SoWptOhOw0jJPmqcCQancU0wH2MKbyvptBJRUC53iMqqVSM9onM3bdLpWfQwjKCba2ohjnIPQINYKlTPt6OnUjzmIAsRjRWulsC2EmyFvpgWp5Jw2WDTLrNbrzURkhr8thLD7ovEhLp2tDQT647xUGhMqFHG0KKlJVzRmMZ+ljPBc2LiIq/Cg3SCWimAnlTFBkiqfA8plChBQBFxABoiXcNTmM7gA4lqHDKoAyUu8gCkSWAM0e21p7oI1NjFJFIEsAerpl6JCOJ5O0BgoJ4BJH/ymZAzGe8AYFIVRBqSlPu9+QShm46sEbhAWpRqRlpBSax0pHwlV0bXcTlUhJQxOUOUSFai1luogvSCrLLzQiBko0LDEirM8xNzLfpfOTc6SE1X6j1mCUYG0Msu0eX3pSSpcM7NRXFYAkEF0FtRYuVr8JLjApMxurUdCVbCwFkkIwlRRZkK+3QyMFZ7mpHMbKvTnlVTTLNCgFWVDprYHqkOGNNpPXBC5CT0G0CFCiJIyJTzjvkeG26K9H2eRTMbQWCA8l9SheVQ6axt3bG6j9VqW9mAjjtZLqFIC64Il0hkSVMABze1UCYJVhRWr3MNKCKdaH7WLM3hdCGAeUBfxVJXVHGSep9IGyctgZsRo3Kk1VlVKBVXhwaK4EtGpCtyqcku1FVb+CtNZWBJ8WmQo91dyfSnmVtFdVRFhLl5gQiRI7WYoweY+hDFFc8yrIgux+b6RnFURqDIK8+wiL6Gm3goTWu1SRYaY5yDJs4mQXCAEQctxwiDqj/w2TiKdq1AG8VArmcgL/bzajxvNJNh6LmEyN525EpvqGc+sY5jBRdMIbN2UdXGlL54iSQQ8n1XLbIHAPoBr9qWwDsdcSsdXU+mQqkrqrLBJBZRsDc++s0JlNuJKUWYden/VzNRQhW+s/LTdmFRdR/45nF3iWFNzUVbMcla3Q2XQncEurYtz8Hta7NG+WHvzmU+amuLUeSDjllVVAFLVRlksShx9G55DwhWhym82azrYFFkyTvrxaBcWjoaq4JPdrNF1fEqjYewE+mpaLsLiL6Am8tqwhU2JwGDBciVUijlNhBLzCFkKRseJKi21wYz6s/oLVCalYYvHgAmRSp1vmWpOXgBPtb3bzcVE4SqCKZMFlpm+qjy+vRHGUsgqAdLrwTahtjABlZgCKjMVBnZDlWNMcR2hVV48H0tUteZp+VeWgIgYly2FsMonhbKphQFHtpJWKpBNPMqKnTY1Y3O4XJLLtBQ1QjQIi3hUp6CU33r/A51ut9Go5ekwn/Qx4UHguq776U9+jTmweGx5tLkjHVlvh9uXo4W1clXYmrn1+aoME2D8kC0+EwJ0ppXTTNluArk5r0RV5LC5kSi9KKUa/gFAxAVxiEQKuCIO1UJHhciVQDCtO0izzYSZ9VWVmjIXnqo3khpwT3Uel1FK5rO8flrbQwjy1OgZVPV3U0zQd6lMZ3plWxoWcs6Bz0U46+KVLBMuKS0wsjVMOf9mZLZz5nvprNKtn4ibvaaAHa11SVxgprEP1HCCxnF/Z/vK03kkj5zsnjj+5kMnTtz3yIf/8Dd+9dIzG0srCyIXooibXcgnQN1SLaberqxW8aryZfUPAXlLXb+V4NmjYrMs423LLcC4gshG+FLMkjxbBBCF6qcKuVQinCdScUUUtv0JRSWuMpipTuC50oxV3vmi9jQQokpvrMWXSBrNfdvUCpWOfaqIWpC2zCRuuvVMJ6vC09T3gqmUSkoQUYzBVoavxrBUh7WAQFxQAZggYWwO25o1npVUbqoImjzYq7PawkIu0v6uTjxbywuXti8lRUYcxwmchX317tJynve3b1xAbuveN7yZQ/byN1/xAkkJkTmvdbDMlUWz1DhyPBc+SrxZxhGgJR6Byp8Ys8DUlC+5mFYBUJWBIVPwJTp91HlMHkMygrQAuQDSq6FCJMkwThWnBTXaQJbK/ZLzKTAuCwi23mHDkpWofZt1rWouI5yagaoKBVbGXoisAckq57X+1q7WShHPpdiEISmVMgVu+37rclWhTHEAA9aAAhvHqmwONK1gWuNTsxy3tEU1UwvmaRHWWgv1pVWOlLi2AwiaCysJ7Ot02o2GN+mlcbybi2a7ttjpdPO8Px72737o9ZPd+LnPPbvvSOg33KiflRaiZmkYVMVnbBpzBmNo5EyV8WP2eTQWtx5PSA2vHSIF5InQAlMICuUyCCbAHZbX6pdu9LIadO9afd3b3nLstlsP33Jru9vled7b2tq+cP35L37j7F9/SQ54za3XsJTjKEkAL+nEI9+DdkALwoUq6/o2OFGDu4q8qvOjmaFoiOSUXQVSegjEcym49s5mxytXWVlVblCM9nJSp3ayqEycW1Hpl6wIhUGqPoPEQxgXpICCYvBYCGlOPI4dTBMswaE6QnMCxLZCLWInpXvHVcHdw5AVoDgZ7SU3Xtl53Vvuxywf7V5tLB1YCmpnvvr3eR9uuWsVT0LVaO5ciwXNC8D7jiyvPXTrsy88OyiiutDYsMBzyZvNEXWWouEFL8wvysKkolM9mlZVTDatEwDgAmPs1ggII1kCEkNvgez0iiztfe+//qm73/zGlWMHgSJ/1Qhn2KeU1dtN/7bgjvsf2vzAB/7Hpz710ueePHLQDULManJ3F3AdGl2aDzkLMaayLA4o4NwAE9CimvertqVg6z64skJThNQGQQigsv5Q1sSmMZIXZaiwiBFVyQmav3Lljsq0hJQgTpupvYeUWuUrBG+Bm+2OCT7riqsKFoGCCYPeAI68/c5MykOd2ns/9s/8fYvpxuUvfOmLF889QzwsXbm+tcEdsptM9h+9BRQ6d/b5iHtKOavH13bPrINXFr5LpwI3gbJS/4yf0MmGVOStnbL9NsVUuMwLseBKcEWI4pl2xiwkmJErIQQnV37pj/7ggXc/0j60zNp11mmLOEXDqMgSEvgs8EQ6jvmksa979zseVnnyyndfCl1EFTAHHA+4VJJoNzDNE2xUI8QUyaY1XzRDmP+oKT+tEJawEN9Uyy3bZKqM36iCOVY8s/wSwbSsikzXghNk2m8AHtnlzuagqLfYoaaD08wRCgMUCLhdiemclIvHs1ANGPYSaB/v1NYOXLp+8dSDr7/lDfeDi6iLbrn9dSePnrry6suKj3IB48lo34l7Xjn38mvXr7T2nbx27frFc2fzcVIPiBjmQaCtZT5woKoahdG0J1GWtcj3NACqOqQFNcjWkFyGtGMCx4UiMX4MUF6Ia6B++0/+bOnw/rwYK5DjXj/vT1zsYuYQRPhgjw/2PIcwIhGV7nLn1lsf2tu+fO35SzUP8Yl+8tEInDYS45lzL+XnIOZQnsv5vrHkleqxUki4KnKW+GVawQGYL5RMS1OljHH55LZnW75EZrCIAghKCMVEKfCd3ZxuDvJ6yzlUZ5DGlOsLCtA7S02qiqoC5HyWjDEMRnDigfs219fPvfDKez/6451OI472EJF4MPExuevuh6QgV155LUkh7K593w9/7A3v+aATdK5dX/ecEAk1urrhu1Bv+gUXZbUWykKjqrpyOmMpgGdKcu0sqFSz+rqq2qXGs3HMwPPAYRrQSwb9PQ33P/Yv/1Wd+YPLV1ORIoQ8J6iFXX5joBqeA4IiBrnMdjc0pq15cjLCeN/3/dhHz3/nMew0+F6f5uB6kOUqcKteBzJysu0t4DBXL1VohoCmlSBlKteSw7Rdp2Tp50qHabv2BAsuLYSxBBnEFDiVM5wWCOfKN1PIZBooSpg0UejwV72CyuSdUiSQmqFQmHUP2jVIbmzyydhJwENMprlH3CwaY1XgMCjk5HWPvu+uR75/UvClQ4edxWUgxd4zTw36/bWFtY5T2z3zKq3j9VeT5tq0a28Sq2qFtrBso77Jh4HabNs60jJ9NhsrlI7exGQRxNGZu4TiwO133H/vfZdffmntyFGe5GHYwJIU/YnDPCFREceOTjiZC0xFE5QKoKQPN9ory/d87/u/+sm/Onlikcc9IGJSAPWqJ5eAWNkL1KCGzRpPM8g319cGYnsx5SNRVj7V1OZKREMUhpIpYvEGITpNl0LKqplsnZK9kt4UJCtMJIWJzEVRAHjKgnpqihvE1veVTWZsfj3tpyoFnToeXD7vL63hBJLeGB88CMTxiBQNiYnjN5pA/IDQGmbICzJRUDHpdhcYI8dPnNw8d84NYNiTC8dJMREw15+ZRUSzJ9Spyhc2/bfhsexUkLKFXVZBpal8GphOQrjjjW+JeIx87/rmdnvlMPHbbnfVabaQx+Ro4kis7WswgIwj5GiAk4p2d7HI0gcffTdegLTIACGZg++V+it42ae1TsmGQ53aGw4ANj+XjREyM5eyiEzLDiWasxI1a3UpVJmvlLbYbQFK1QBCN9X0bRXXfAEmBGOi5Y4wJqq8HanceNUInH5NUy9MgBLpOJDGewBQb7R0kgEM1RdprQsF5KO0GEXAsUp5tLPnYooVv+u+e48cPnL2mcef+8ZneQFBF4/GotwZqPI9WUX3Kt6X9CeCqM2KrMKC8UjYdnOo1mDHMQkvhkQUvIClffu2xnvjSUKchtrcOXHqdnAZYiRPJg53imjAQCI/FLyQXDDHBaXyUR+7QffEie/7p//si7/zn48d7ap8Vzm2mQAw3+6YcxeoIn5NmxjWYU7D5KwIbqCg4P/4lzMUgKsPqrkES1UlD1zCXcqAUQdhRXGGqEMZpTgm1OS/GvuZ1MXw96wpYHITSpx6i9EEaA1Ge6m/ANh1rrx2fmdnPZoMyN72fQ++Oc/42Rdf2Nvt7Q5HbrN1y+n7Tr31hB+2HQrPfv07//wTv/aZ//f3ot2eW8OqL2ftOVkpnHZCJpiIcnMwUeTR/RXjpPoANq1zSgG5kCEgHshM52RQg+VbTwwLETQXkB+Set2v1VxMRW/AhImdCGPHlVFCsEvcGrAGkFBMeszxYRx3Gu1nn/5CwsfAbN+n7NBaz172oUpigMlTTeeBUA2dtbvlFZdA3SQ5JWZQYgoOy2qqqcQi6/2sG6ySubJwb9Jja6kCQeFy05iU0s+3InSjJ+oBP9x1UZ66hUaqwgIlBVSCsF6hgsrYhlUF9SRIhsWBe06ffPB+zBBDKtnZPvPFz1x5+hlvcW2cFtcvrp/5/Bd8Tt/01rdBkZy/dq7VqK0e3PeGdz16+Lb7Jnvx+lMvtHxTacAGNNk+tmlUWT6qUnN1JgnUlPkN2rZdX5OHYQQscDkU0iACnutHLQQIIVzKJsPBQq292GyLONnb3vVBEV64YV3q2J8LraEMiiKJ+vEk3rh8ZuP6+unXv+3ahctimDk+dBcXBjf2wIcpW8l+T5OeMsyUnFVlayJSwnwyUCYbzPAB4SYLLjHOHGFuSg6afnDGPYAZPQmVpM/y/XrHtEoJy5WaRzxqejWozBqVNYoCChqwRnfx/R/9qUk82bp+9fqV125cuXL87jvrq4unHnhjKoTrvffZr31uY7DJKew/ebK5dgCybPfKJjC31uggD5K4aovKqp5VtRk4nwUO65Bo2Tq3WIAYblsFdZSSGkDnwIwuOACMMsqYw5yVdmcprIHCEzEhGElEijhhjgPUxXEUjQab167ubm6naRbduISZf+PC1QtnXsaKrO4/uX727MISimxeqGZMJ5gmpqQEjbYeb8vWN0lurn6NyFwjScI8ZxWmyeWUrIXK1hXMFVot5LMpqS3fV5qg3y1NhjblFQhr8crQmexllcG3VaVbYR4nahKPwPVrjAS1W1qt8MGH37Lv0EFOKfjk9je8bnL8yP7bjh2+9VR9bQl8H4oiT/LheHLj/Lc6y/tGPWgdo8mIz5hOaAbIS5aT7Y+azaPTnpwhIiibaQkOWZwVhiQCAhgBRAkIkcZxKBQXWTEeFdRnzLvx4ovXNq573fZkY/vQwQPA0zPf/BJPo3ZnxfVqeV5IQm6798Fv/sMX737jW5kfvvTNzwKDvaHym9U+2TTLYq2qa4GZ2SY+MyCNwW7O/GYValKVy/HMrKcJ37SeftNvxKwsPvW65p2yrARhwxzCZY+65A1VFyl5VjCjCpaVFAmswdy8CFvN7auX2yvLrFlfwKsynsSjQbCyAumkVguvnHnh8JHD9YUmiAx4YOAWaneWSTIKgVIPkohbhrHp3MwKHVBhUVxlxqBMUqEqMvCUB6fVLQOOwUElKM1TEadw8bWXX3f8pB8E3Vo9vr7Oe6OGInefvi88uOojCuMBZOnBpW48HGxcvxKEzWN33L1x7WLO4fz55+9757u//+O/sHxs/9f/7lOB76F4Mo8LVNWvkKqMkWDmJQSf60NVNlcizwoKTcUzJSOVxFQ0A+Iw3/fAFTO5suaSvV8GUvtOHYANUCeE6kvbECMQzCjt1nOI2QoRhizJgUASTTiXnHOW54BlkgzFIBrluaBukeQ+cZLBoL62Gvd23ZqDqEgmk3Z9oXP/yb/4N79OiE6dxZRwK2cBftqjgGlJyBIvMCndi+0i2S6RVigTURymAzgxtIDNi+cGg149DJ/9xlf6z710z8l7Vk6eHAOmrquuXx/1tokUoe8Et5zo7t+XD4d8PCiybP3K1Y/8i18+fPL2jYuvnrjrzi/9NR+OJ53KqnCFm03pRAMMi5vJtJsoyy72lOxaTTropXI5sy1UBTmYBo+ba3KWkT1PHFFT1y0s81ODTwOlONYJhiKUYY2URDnsY2kDU356da9pqdmhQH067u2tru5DDgOegSjSeIjGxaWXz46LvNVcPnXqzr2dTYiToN6BQuXJJGDO9rWr8eDV57/2mdAHgsoCm2WTTLOL+brVFAnrWEhNQsaLMsIT24YGEIZqRxlQhLGirRraHo72rR5Ugt99173h6x+58Vd/d/3FFw/+6AeV76IicUD6NU/b7PrFjevXAZOlfQcPLi8fPHj0wrlXn//al5987Mutdo1kUG9qcxJiziOVKQEi2GJEsK+WTFlVMbLhpgqqxodorm1b6sFNqGfGMaj6QmXju5ogILTSIWYKArhszZumr2ERoOkAUBm7+bQeZB2DKEcdtAFwEAXfvfDad776lYO33LJ2bA0wWWi3odHunLytJDEEjbUw1PcjDFLO9Drxn/77//2Vr24fOkSadZYOC+zNFl0mMKriY97MyqTzUrW41BL0tBCpkhkAKyXueEG0nY3H4+NHDztCwe7uC5//LF3ed/AD70tSCIqcqMJwXQtw6NJSV5uT4jovKWI1mqwsLL3jHe/503/7O8sHwVNKTH29VnykTDEIE4KwnPaMLG/fuj4bCytpV0HRcGphmvPN1cShmAuNc2BVVVVTDWVFhVdx1fcGggxfzQjQlAU0plGmWqw/LiyxgZv1qJus0DJUEbdMOPVnv/Xvf/xXfnHtxEE1nvA8eeXbLzz//NOF4zlOeO8Db2wvtBdq+6mdaBJCZvnm+e1b7l/GcZSOJr4Hqby5uF9BGzw3uWcXQN512BicKIc8UEXNQFSnho4LmBJBlUQC8rQbwPn1G29/5H1cw1R/AkjVWavj1zwiNjYQo8hlqN5Q1JGYEexCLtNok3mB01pAQV0QcuapLy2ueEXCpWNKmkQnW6Zca9YjpaIl30JO4TqZNWyhQp7TbJ2LWdquSjKnKUlTw2qxNJGKo6Ys2XzOKk3vWl8tV+BiYEgVDuQexBm5tCPdpfBEy8Fx4hZKf5ABMgRaAuUcz7QfxIXpl0lwPLI1Vo0O1CfgAb3lze8ELtcvX6O+esPb3nHvPQ/d+bqHslE/SYYLiy2QaaFyEjTWr28/9uefO9xF7miiswK8rHBUPqx1/qbIzqWZA5mWOMwbKFTFSWTyREKrOaYqikohLBXThslrL5y7eun80TvvhVzc+T1vIg4pIAMpSRBqpxaEwBgSkmjLkgohv9WN0/TcuYuK1g4dO8oCMuiljRrEfGZV1nuXRAp1E9vMzjDgirk23yu32ATf3E6rEIpBPHhKR6t8ESrRvxU2NVTM6bjBlLdHCDDHJVBInRdzDV/IjKiOzYgFxrN6cjkQaoQ6GojuETLui8Z++tJTj735+qXWamvl8EHH9wbX1s+/9PSBI7esnbodfCLyiRCSEJoVxfr6evMQGWUTwiBcbvb6A78S3nSO0NLeqRl4kFJOid5UVUwkhMpGtsVynJeTHKXMq0w5pHD2zDNHH3w9yBi1AsD43JNP5ZDdd9u9KotRIaFI0mhCJGChCKaSUBKwfUdOFgI7fsCCgOFEMQmZnPYLFZ7y/MpKDcCsa2/Z5TBHtJmODZdEb5sNiKqKb6nDFNB8+bRCrVLOZsbKOUWbtBCGZDGlBWHKMJTMC0RKYoBtBmBloiYqQ6lEVRKil0ScBZEVArmQp5wjePHM069feytySbSzw7lcO3I07LTjySgeponMlg+siTSPsqTWXuAUlAtOA7a2Bl6boInhC5KycqtRnhnkMwO9pVe3VFZaetWKba2mDdWqrWrHHqAyi4YLj3/2v73p3e9uLq+owgU3OH77HULxaGcniiZNCYRRnhaI0DxOlRQJUl6t1V5c5BwoxcwLinxCFEJOxaqGciiwdI+lZc0kWCZeBOZr9tjQbY2NqpJUV1XUbKizTgTPNZLK0DLX8tV+WJWo3QIZxDR6EiWjzVzBIFJCZcn3MaNuxMTIkv6Dq8lkUxhxEUwi6K6Q0VVRa3o3rlwS+Zv6W9vtsNFtL4LjAXWKJGGcJgn0o3S4cf3oqdMbO73Lr4i1NzZ5b9huQB4LS70VCoiZ1tEqKCq3pGZVC73C6RihVXbLhNCe06wYmRkqZOZx7OauLC71r/Fz584AwzlWMhr6y0u1Vpv4AVCW5Xk0ieIoTtIszrI4K+KcZ1xE0YRQ7B9YO3TiVBoZEpU1HhuZqvk/qHomhFT0LFpNrtAZdXO+Jl5kUOQm5aiGe6kD1MFTtv88WJuv0ik5B44UCM4tm8iyXrnh8GBMCEGIUjAMD6jor7LyBISYFVI7XaukkCqHRghFJDwGmCMkkOMEO5tb2GOjUW97/eJkb2M07hU8S/PitXOv1Bc7tLtQ6y6FS9BfH5IcGhi8VCeg3FQYcwl5AQUvldIGe/OMwFxTzS7Ha438zHEFJqmoxqlty9iSjqyTgXzSbMKVCy89+Mijru+JbIQxKrLEa7VYvUYaDZBKTMb9vd2t3k40HNJ6iwtwGCvSKOlvpJNtxCGZAAlm6aqF5sjYo0RzTSWYlVemo4RleamatadsViixKmiuKW3jBVdtCpuIW+Y/zNNiacmaEZkhHOPpEIe07BOgBGFDCEYKkWkRpyT/T5WpnNww1ikZpCn4ngu0eX1zE3W6nbXDzzz3HYbRqy89k0Zxa/mg47fceve2ex5YPX5AiuzQ3ac//tv/6e8/+esq6+3u5EGIcqJQ1ZHGU8ooLg1JQlmyl8hUZ3A1+yRFSaXF1A4PVMpuUh/bnBPjuFGHvRtXQQlsy8NCCK30HDMqk7hI00Fvb2NjfWd7QxTFZPO663rA86i3u3PtjEhFswuOD5PEYGA58+nT/GE6IzFljQJU7s78TPCsO+84JVIVvPygMucj2FkLe5zB7AiDOY8Kc2ScaTVDKg2JteCYgyGVUirFlZlXl1WKVo6T4RmleNrYUgqGXG+dv1LnUbC1EQVhCLX62r33ufX8ia99Lkq34/H29sYrD3/fT51+4M1eu5tlY/AdN/Tveuejn//7P7985rHFQ90oTyGbEGTGdLiZ/ixM4dME8imzwt6XkipKW/qC5URTx8w/oxl2L6d5MXZ9RJXI4gkAyqPYNI7A8/1CZYS5MstyKU1/Oljaf9gLA6LQ3taNFx774mjrCgPodGk85tirphqqYR8hS4hvy6RzkbDC7gLmp1ZtCU2ZKSo0VwSfklmUcXfTET1VkfOAzN4sqwNGTD8SA7IUbotIPQyjiswtbU+qHJlAc1P21di3rI5swAtO7OSjdOxK/OrV8c984gcEIwh53SOH37300bPPPf7SE988dftDdzzwjsluBEHhLtYKRHOef+EL//A3X33s8DJ43Q4Aca6exQQhTDDFiEuFORT6iTwfqoNDys2h1hhLUGH0KEeQ58BN+AkZQAoyBb8O2EFJIictGGxBJ2dAfOkCcrAcc5mhwiGEYezU65hBBjnLhzKZxOqJ//k/rr/08soBqAXaerKcKw/GqXl+ChQzKSRGElHD7CvMGqZ00KoCqZTGAVCFavtVjjQIQHNpnzKxAIsyobRleu3lUAm5ZcXsVaaFBKpk3AgusQOQl1UbIZmZGWIUOSiWvoezQEYFeDl4ANIDKLRPFnb41tGiLYw+DXCeFE4vqQ947UUx3Jn0CYMiHkHEMfFOnLqf4fragaNFnvSSPUeGKjvmru574akn/82v/0vRau0tHMV4aXdz++7lt+xuXVFJf//SQptK3L/qcuECZOYBOQFuGCdEmSlfMT1XBJf4Sqsz16tMFfguCtp+lsRxqsKW04/yjEP3wGEAToIgjceeH2KeizwTaZyOxyLKilxnBp7j1Wq1Xr/XOtylDZlnPe10c3BM+EFGJFwV2pu7yJw1IbRfFWYemleF6Qqs2mTDnsdTDvdai6GlyVqTmtIS7UkNZa6JQYiSUCJymBtdmvU0SqyAqwEgM+WLZQHEwQxhBoyCg8DBgDiI1HhRY4sCQ05wBiQrigLDbhRs7caq5vUjniv4ylee+KEf/2lEZI4irMBpNcOlop+h0Akbq82ChWFn9cqrF37gBz7QWG53FlcNGYVPxoPHzr946s7THl24uH2hpiYr9UbdE3E8ptx0AzOgsizQ0ykcVdW5JWUumUOj2eBZFA+ECGO3Xlci6g/yCYf6avu9H/4nENQKHgftNgxScDw3SyDLGNCd7Wv94cBfXG51Fhb2Hdh/6t7tzSsidETQwDhDyV4+ygkHMMR1WUIYJZUdJQJLu53OP6JqDEzN/dcmrxJXeAfNuvklo5BiWUjbuwZsQgipskM1A6Lz4xkCGRhv5mY5taU0ZU68oNTzGCkESGKKO8gsUgJIjAviJIrFAo84j4RbCDnIF7ez3dFknAi8eOTUV776zHNPvXr6oYeyjANG/fF4jDvNzkJz9RB4AQD+zBe+/Av/4n9ZPnYrwmQ4TDxXLnWWKaOvDIqugFa9kbbWEMqvi8yVxfKBY2TrkiMKJ45IapqAvpnynbGG5pTRdaFIRxhDc8nLZdqfjJUHyofO4tFg5WTnxCnwnWyyG3geuADUhfEYkgKKwuXF8uJi4/ChSAFptrzO4pmv/MPSrSeJ4yPFDywcw+pquhf5QvtVc9pL1aA3NRShZkAGV+Pt0vRJ7IAkVFPXjMzGYsojlaCs1AOSQlSmhm4izmA261QgmFXMadm1r5hERoSYYcUIc1wM3Lp3YQO2R4VoJTwfJsWQy6FAI8VGHFIhNnd69XY34xlGZCcbXtvb/o3f/o+/8on6Xa+/G0ThHcDtW/Wd03H03aef+69//hef+m9/fPjI0QLXOq3WAlXbW9eyPK81W5uR2h6m4NYKFUpS4w4eJMNJgjrN/W2C0HgLwzaSIDxLf5qmSnPFKmBmvLiADKcpQOpC2G2GnWVOD37nmafxH/zhT/z8x9srh5LdTb+xoHoxYi44QbFxMfQDsm9ZOcylAeTi2089f2FHjJcSjguebuOwcezQXRBeQdEYZKxACA7WFxJDI5W0PJhnllpUPDNc9WanpZlpFwlXZxpNuxzzUzXYVKeweUA2XxaHuayfmYMCjEKYo28EAUXNETZSpFwUOvC7egFxRjNZi1HYT4vdQd7LREy8mNVjIIlQjbXaKO6DixYWWlkqlldO/8Xn/urLX//GX3/+M0eOHr529eq5cy9fvbb+lW985xtPPgeKLh85GbSamLBOe/HlF545dHANEYYwPn70ICqwizzPd5PJZDCK0rRwHJzub6rAzymqoxFP09xF1Pr0snWHZ0M6GYAfwqgHWQz+UqfeWCGNdozJzqAI127//T/6k+2E/9Of/onFfYfyGzskqJMoBjTJuQw7zeFwoJjXOnDyyX/46refeo4u1HpccscZZmjrOy8/8vB9q91bXS9BcpJnmxBvSwSuOXdA5IDdchz8phm2yp4orYArnhHXYG5meJq8MzoTraUn2bxiqrJqbtpbvx9jVR1PYQyam9KMQpgLmRYcUADYh5T7vaEaCDzCchCpXqomOZauh7waog6TgGnSqPmu76bxJMmLCY+P3XZkOJy88wM/vNxpXrnwKiiFvbr0An91qd1ZPNJlSTTpttvbGxuBG+zs7K2urUrHI1T1B1v7V1dqYX3Sm6Tjgvrt9uLyZrqH/TAhLRks+/hKLAl516EytEx5kvabuzCMABy0eOIB6a1sjdQEtXYjkroBRzXmLXz+7z7z0ovP3nbi1PLh4wUHpXjc60GWOZ4nar6/um97a/DJ3/rdC6Oxs9CJXWcgUul5569HO/1NFbQyXMu9QAUeZ4WiCXEAGBJWSFXmYEn1qGLsl0CUlDhlyuckZNZ3tF6R2Jk8clO0Q3PjFqXw5qik5kg/ZSlV4MIoCi5fT90m3HIwZJORg0B4kGLYG8P1ntqTrUtJMuSQEVd5NRY2Xa/mMs/3PEelHnWJclSOuwsH1je3nXqIXXf/4uqRw/sLngVN/+jJI/V2UxC0uNgR423IsrrvFJNJNJ7sO3BgGE9yIV+48GK97q8ur07Go+2tba/RIl4wiJKMc8zCLBceFgwVGWdls2mq2rg6pC7BgKmz79gb3Nr+J5986ep2Xls9tb6dZQ5XyGs3l2v11qUL5z71+78fx8nJ2+5qLbZdRBylsmTsHzsaCfmff+8/fevLT7Zvv23IM7/Turx9o7tvbf/xA2de2YgEf/XaOncIDoBDHGcTLsusxsVz21r1v6bcGVll6BjPFZfpXOO+ou0iCfNjebOynKz8rT08CpXlD5062l8yUC70x+7F65nbQLcdqrN05FPgHuzlsLkrt0eQhytX4qFiAQubXth0HZ9S5lLXpSwgRKSCqlAJL+MaQBMnSCTv+D6h0iWq3W7GaQwUe77PgQcyboSBynLFxXg0bC50nCAcJUmvuJFkcavR9vyQup7EZJglghCacMZCyXmToTo2Ofj79xu6gAc5B50LuBDnUCBK99/RPnwfW7jlG8+89sXHXzz2hjcMxARCGQgXCY4xj6KR4wbdlYNf+8rX/6/f/b+vv3L+lWub3uFb1h58y4Xr/Z/7iZ957vFnG93OpOu7oUeQart+oFCDeQ2Pnju7ceSWw1c3b7Tbh/PMzyMiU9mqLULBiQMUSZ8YCjLRvoxPZIghz7QzpMR8G9SKzIGUwGZFr5mHFGW91J4nZI89K0sqtPovKSOIPTnJoYuRiB0P3EJnNddH4to2dRx6x1EvHo69Gk5o96U+v0iWLkRZLWzL1gryfL/R5lhFecqJKFBR4BwDS3khEGc+BVIwkAEF3yS/GJMoy5I0I4wRJWQyrmE5Gg+DMJwUaUFhUCSNTitKI4fSrY3t8W6eF1mr2UmTeLi940ulJiOfgR+4hJICo4T4qLmfvOeEOQfQ1Q9cSIhSQJ7TWj01oaGEYLsfP/nEsxuD+NTdt3q+QxjykGeL+LzgzGGNRtP3g3qj/u3vPv7Y4098+tN/fun6lV/917/ciwZBtw2BHzN7xBlSoChlnucxxrCaYMclgIgUdS/c27zqO47I87C54mAOMkWyzOc0rDcHqhVQ9tbLqUz7Xzo7/Gs6n4arM5BK9zsdRZg7bGPGr0GlHcuCs1AgCUSA04KNIXvhSr60Ep46gFEauQwPObo8KnZFsDdKPK/m+nWeFljHSqmxPXUIEIppLjhhLMnTrOCSC9f1mo2W4zjrW1uL3W6moSHs7u44rru8tFQURei6DmUg1WQ82t3dOXLwSBJPwsAveJEmY6l1y4nHcZImjucRhJnnFEYA7XoYaE1QmNTryAFuqqi0BrWVTvPQ6dbhO6/1RK9wt8f51c1dIIhgQgl1CTN4XRRFQQiSQsbxWAgxGY8atxysHV3ou/Dl577lHG61bjsqlptpx3ddjzkOoZQxx57QypizvLKfKdGo1TOu+nE0KvCg8LcztpXSVLnccq5Z2djTwLjK2TG+CaPOkxKmMyGGA17VMKtyKK7IqBjNqjPlPKyRtBB5PIEs0e/MM6AOxQB5FlGqXB8UwXGaRRwmisTYjznIvFB5QYTysOtiTxYkj0URy5wQGoapUH6tgZnre2E8nqSj6PTtdzFEzl+8sLS4uLZvjWGSp9mw16OC8KRgCjGFPUxcQie9ERHgUtaotyllUso4S9M8z/OCKxklScFVJvhwMprEEwGA42yMfcCeRtIZAITLsrayJ5ytmA05i4BNBGotdjDGSHLEc2mPAUWKMqYBt5Se5/iBl2QpOO7d993TXt73/h/6MVarXd65EfHC8zzTsEFm5o8IIZRSjuPWXN9jrN1e2Nkb0cb+Efix0z273tsZpNhbRX55aoPtrYAp22r9scilmuczx7+W3xjPsr352YzZiOHceRAIKpZJdXCK30BhC4K6/n2RA0YOAXA8qoQghHCBB+N8kkMsiCSOkGQwGgwnoyRPOFISQ46AMwKeXuV2b9BsLWzu7LZbnWGv5zK/0WjvbG11Owu3nzy1urjsUKqkSOK42WgFnu8xFymMFNS8GgjJCx76dZnLml9r1BuEOJxzgYAjhRzm+CFxPMrclPNMKMRcKgpzewDhgNe9hS0cT3CrNyqI3xlnKkrygZBHWm3HIVJJzoERVNiznbX8BMHEC2vaNe+Svb2dCI1WVlYvnzuvckkFaYbtPOdCCCklIZQQB2OqlKLUocrQoxRS1M8UTfOceF4/5tfFZHVlGZGhiGJsBp2EMBkCuylZnDYCmTPjjpbzGJamjm/KLP+Rv0VzfSL7nSUK1wzdPQZ/EULo5tCfDNM886ggSVL0+hArjZld4ukM0sWyKDKVUnAkIoqazyKFc+VhNhlFnbDd3x2EYf3SxSu+7794/pXtra2Ll66uX702GA+LvMgz/uCbHkrywmFMGl6M43rjKO4P+1evXyWEetgZ9XtjOZ7Eseu6iLleUMeUTNKMINoNXMxEkqU08CDhECugzbXEWeonVDGGvAbAuNcbuMafODVqD1iixDFnBQqikwBlmGeK84xSvNJa9IFxzrNhtHtto9NZOLByKHTD4aRv3sNNy02rPecF55waiLG5tdNsd7c2bjAqYTiqtRe3N3pRAXU/ABzbg5tkRfWEefoamg0oTYdJ0U30mbkoaPC2lDDP5J+SX0oSkQNJCguNBV7sTcYwnkQN7Hg+YFrHMshGu0mckzAMiB+4RdtBsYqEKgIEgTZxLBBOJS+KPMzRYmthNBq6rnfh/KVWq0WoW2+2P/qhH73zrrsa7db+Awe2trafeOLb337iO3fffufzL768sb3dbrcFqHqzhSkJ6s294dClBIEcDPu+W+MIWvWmIjhVPB+nKSJ1l6WFcFSBFKFEgueDcBfSYN+5q4Pz1y4vrR0/cvyUR8nuxm7z4L6FENUD3wxXAiaMy8QEQu3LavU6RjRNE0pZlqNOZ8l13UtXLjt+bbvfF0KI4YggTClFFkVKJJQscm2WNtWOoiRsCG3iaZKrOFhYG2N3qz/oMOEQ038mZTHFAktVcaKgYhSWrOfKb9qxeriJqTuDM7OZoOr82ZsO6dTJopOMAbVgsNPvyXyJtCltIVnwYpwlOQ21vThSNADx/q4DpCEFSeKUI0qoRxhxWABhMoh8SaNo8s63vzMp8p/+Xz92YP/BpcWWH4amuaD1+MMf/rGtrW2M8X//2//5p3/2X8OFZqK4ZHiYZdh3McbxYOC6nigUqpHQaQRhrSiKOMkQQn5QC2oNKcZC5NTzaeDDWEHKUSzcDJNRMZTbA0LXD+5buvza2TydgFR+4AehP4mSnIvQd7M01daWZa7rE6ZzE0rpINqN46jdXUwFv3Tlam8QHTi46jUbRRwzyiilhnRlDpBAiFKquFCAfd/f3d3Zv7qyeX0YOI7kKfE6w8Fu1mIBhcywWLE9DluWA2yzaXIoJ32mIy+EzPUO5c0SVOXJGdNzk2DuwFw7LsN82FvfcBB89Od+5+CZ/td/6TfzhHNJVVakEx4NoAjyIhtSkYoCjjQWEHGYV9ve3d67tKMYai/va6+sqkJ0vMYwHv/W7/3eHffcIxG4NX88jj2H8jSTSjmeGyVRnmWuw7K8uOvOu7f2/mO90WCOg4gcReNCiYV6c2trOwzqnuc5jiMlIoQUWo+Jz/xxWqRBXmNYKRwnCZ1giBBA2OzxWIT1YLF1/pWzWTZ0w9ubKwsvvPzimx55R80PokmKBDCFJ9EICM7yPKw3KWHj8VgolGT54srKK6+c+9KXvv7zP/+zf/zHf/i6u+99+plnrlzYQOC52jSy5ZXa0eP7MUWj4XChvTIoUsdxfRZce/WsAt5ZPHDp2pWT3SNpi1zo797qn0jSV3yZohTAhwSAirLHZE/502HSHt08xwe3JxBb4CrmxoKgmgLCqGwfIlLW6ooMslS/yGou38PjG8kP/sIv4+7x8+f/oAkSkmA1b13YunDxfPLDH3vPE0+88txz58dY/drf/tlD97xea1CRxCK+urNx9sUXvvwXf7n+1FMQkY2JeMdPfeSu+++4cPXqgYNHQULIgse+/Z0HHnzA83ES588//1IUjR955B3f/NbXf/af/9LR/YcVR2mc8SLTMvNoFmd+vRFlCTASBmGWZ0UWB65X8EwqvtZsgcz4pEiZG9Q6tMgA12rMa6HYU0BdN2g1F10/vLG1AUr1o9Febw91MQeV51ktrGVZEYY1c7QB5OYry/KL519d3xsudBc/8pEPnThx4o//+L/cdtudL7/8UpKku5uj7e3Nt77t4T/4g997+eVnV1aWO63l7Z31sN61hE+McZIkeZ6b8+s4cZxJXMRZ1qI1KVLJgdkOVEW2IHN0h/npiGlzahrwZn9qY65lgcxZAIgYqoAZ9HI9wMjNt7MLr8G93/fwgdfdt/7SmfVnn8oA/LXWjoj30skv/rtfOf3o/+89m6Mfff8Hx5u73/rsF92NnifFUr3RXu6cbAen3vv+9z/6rl6vx3eyJ5/97tkLl194+vG77n2AeIjHieCi1ak99thXH/6eh/3AOX3P6cD3kiLf6Y1evXzp/oWmS6iODxQrJWVRIADfD6JokmV5nCSMMdd1HcfBGAeOa+ggyvN8SokwqAIUaiQiSHKkFKn79T0Wbu701xr7Rv0+80OnXh9EUdtxnaA2iOJkNBnHWlnGe3t+EGQ8rzWbv/sfPgnEbbfbp0/f4/l+mmSe5952+215Wjium0ySLOUrywd/7md+vt2sXb1+1nVdIYXk3NNffpYlRZFjjNM0DQNnN4VhnC51mlT2lRTTwoqS5uj4OVLhTX9XZm5eUM2NvExPY7b/zIryfHtQZjjbw4LLeJJtbsMjH///v+6tjwJPrjz39KJihxx47dr1M/X1D/2TT5x+8O299Z0nnz/nLi15O3svfOmxY6ut47ecbN5xBC/V8yJx1ARnedevw1373nf6+LujJEpzEe/wuOf6NeqQ40dWRTZK4iFAAAjpfA47UcZJy+FIFEWei8yhDGmQjyfDUVAPzMg/klJQ4mFMMMae5+m45vsWIXKuarU6xS5kkg4SNYoxcr1W0wsb/deurtf2NbfGPSGLTIlJNMKeQ1O8s70dENb06llWMDe4cWNTKfmBH/zRR7/3XVeub62trUkDEvIi9/xA+1ghnnnmuQcfejBOR5Q1ADrDoWi0VuP8+ng0NgmiE4a1Xm9XSokQjuMIUcQpGuZoXBDGFrHa5KLq684fCFhhmLJTjW5CrdMDu2ejgVX4ZE5FRi0PD5ZZpnP5N3/8Z9/wzvflk/jVJ79NkIrWN2kO+7l4z499/IGHH51sjzrHb33vidNv/+AP//5v/taDt93xpg9/L/T7cmt78NQrO3tb9Vaz5oe1WmuQbuV50Wx0Gl4AcpIkOZAsiuJw6dA9p0+BwsCQVCROE6O7KSY8zSeB43Y6zWQcF2lWc3yX0DjJpFD1WstkYkxI4EL5jAmeI0y1XzFTDAjplBg4cRLpZpJAIUKfdNqdheXVi9evdtsLvbS32x96zBmMJrvb27VafXV5eTgcOg5Lk3R5/6HLV89/6k8/dej4kXvvecgxOdqrr7761FPPfOhDH6rValEUv/TSmVOnTrbb7a996bvfevLlu04d666GxZhL8ydslFKu61mHLKVIkpgT5De6k4LsRDxo10MvU3EfV5Tzf8STsEyZKmMvX1XVnwOaHfFU/SztQey4DI25ObaVeVBvdd74gx8evHKpgb2TJ0/fYN7o7/72nT/yrre97yPH3nxydO7qVz73zYc/2A0Pr2HfeeL5J7/2D5957PHH/LwIJFeQu13/oXc9Wl/eN1Gq1fJ1PDDnsIHgDnA+3vEIG21cJsyhTsCCGvYaNQ0Dc5VFDYLrDIMQRZpRKQngIs+UQBLzvMjN310w54kJoSg1BH6SxDHCOPADAMiyjEoEBcYc+xlV6XCo0glHqL3Yita3QKpmo9kIauPJiCLiOG69Vu8NJovdpTiJiyK7duNaFEeU0k/86r/6zN98XQHiQhw4dDisN556+tk4jt/0pjf+wAd/sNWqA8Cd99ymgHBB93pD4rieorzgxq3LyaRotdKiyM2B1bBUayXI2Y0mC3XPc2uY9Kk5dgnUTZ2jabag5qa31dzwPp4OI04nSe0IS2EPztaIRpTTl0Vx7uKN58617n+zyvnSLXd84r9/unbgJAyL649/89zL1y5cXX+Y+k8/9p3lI6s/9KM/8tGf/PhTZ87/0R996j3f/25zHFECgQ+7fYjzfPuqU6vJKJcWQTabkOeQZQ3Hk0UmeM7zFLkRa3Sw48h4l+9sussrGGg6Gvl+TRAyGAxCv+5gOhxGlFCBhcsAMUoQ44XeL22XiCilcQQhjCIKmcgThIjrKdSfxIMMyCDL9y/u29rZqoU1l7lX9wbdZnuh0aaIhM0Ol1gqPElixwt9KfZG/aNHjriOAwBJHBNCl5eWkcLNVjPPC9f1udBKcvjw6qNve+jxb545fqufJoJRbOttpTHpKF4IIYgKgTqpYCMBgzhtM+y45m+rwExOMBsXmLWFVTVEb3uKdvxleiKfndbAGIhLi5xLI3jKAOWQRDDsj7cuXpxkcZZzt9OlVKLB5uj8uUZ3ZWVp8fNf/hZdXeEYPfGFL912+/GH3/7G/+0Xf/L/+Hd/srO7c/6Lj0VXrkXj3QKKOE+P3XIqV73u/v3N5UUnDHUmPBxjDcgdiCam+E4Ao3QUZdEgaDZx3gsj1b9waWn1QMcPcq6yNBVCBEEQTYbj8Xix2zUpOGCs84o8zxDCrusipK2FAjiuS4kL6WgSFTHzmt2FDo9lFkW9aHBs/9E8SUeTcTQa1zw/8H0phBeGtUZrb6+3vrEehn6UxnvDXqMe3ti6sbOz4wdBvd7MixwAr+5bEULaM5GFShASjuvedfetj3/zzNUrmwurUsnCeglCqOuYTFHbQuFgrH2T/iaTJM0btEbt8Wczz4mmh1hASSS04w0CqhHJipo2JfBPZ/aLnNuDPWxxx/UxoYhS0T26f//b3wlBR/XG6fnXemfPpTIL77v3tXNnPvvZv/i1//L5vdEwUOhgewHG8b13nb4XB3/7f/6Hc4dWWwvhTv/qOOnno1GnufAjP/STd9x3GNZWs94GcWkyTghICtjJ+Wg0aix2wc1cxzUjLvm73/XI+sXep//s/3n1xmBh/wpiAQ0Cx3G2dneiIs+T1HM913Xt32SxZ/YHYVAUeuvCWo1gLDgnb7771kR2ExlmqUzTTAiR54IIce3GxTiLhCoa7UZSJI7vEI/W202UZtF4N4v6SbLjeArIBJN8EG/+4Pd+bN/Kkr6JIlIWGEvOMwQ8xUhgpMBXCAZ98Zd/+eXVpTVcNAKlqPK4VDU/2Lpxw3W5G7JxkrR8J/DrAjus1shzwTAsNlZk2gckCQEswGUav6SJmU0k4Jlz7YgZ98W4LI3a+ebyL7KY8qOdmMQU/EDbqOOY7gQHgoiSMBmo4uDpg8v75dmXn/nTP7ny3ccUKW4Mrl3ZeO2vv/btpUN3P/TWRx//7uOf/M1/e9exw9s31jmhn/7sZ8fLtb85+9pwoT3I3SjxLlzeS7zGR3/tF+rHjya7fSlpNsyQYC7xknGS7iSjvdGll89QgLDbNn9eDNda7e955wP3vf6e7Z1Xn/rulSbO1lr7+E4WXettB31W99y6Py4SGga0VuOEZgg5WeYwRh1HIRKEtayQ1E73YIyZ5ziMIEkwowXgJB5GcWRTEKUkc5iS6sbG+rWzF/at7S+KIhG849UnWb43GgwTuTe4pvBJA5Q4IVTbu8OURtA6geZ8TIl/YJEdqCfR9fP7D3RDn6RFnozGqO4tLC/3o16eZO2FDqPYdQApXui7j7LxWLQ6nkM44sL8fQ2zWmA+YAcKCSKH8u+qVY17arCrTGdFbVKxZAmBotDfto6DKTCPYC6pIw6p2sWvfp0KlWN4/Itfvu3++ySCNJ5c6e1eufLyRz7xG/e84U1vfM/bl44dbiwvvLB1LVIw2NpZ+f/oeg8ouc7rTPD+6f0vVO6cgAbQCAwgaeYsihZFjSjLlLjyzNiyZ8dB6zRe+Rx7PJZE2dIeH3str5Os9VArHkscK9umAkklUhTBKBIUQYIgkQg0GujcXfHFP+55r7obECW3joBmseqxqu679373/vd+356JWOqBUn1x9tzuq6/+x//1T4M7x1549PFzp880l1vEAgMzf+bU/sv30zQZGR2MhDr66uGxsL3zkksQy0QaWk5vuP6Gy+777A+eeOqrX/zytx9+AQD2TI1zgzDmAySQRnFwVDthHILAVyCyqBOQqlYyw8j3PPKWy8Zj7UaklFoUJWHcabc7vfV2z2VOkmRa2Xq1oZWtVuu9XnhqdpZbPTY+0emFqTADI+PtKDPYq9fHbrrq8qmpCQDgjocx0gpaLfHD514+ffjlgTr3eAamx1GiklXRXTxx4oeeESPDDZ2kiOKuTFtKIM9vdXsN7np+JQ+GIvRJ5pr2QIX6rotRjIq2Gd2a9Cn6nP3Fov4kP8D5pXhScHNidJ7Vuo9DHR+22MAL+n0shQx7UJ255pK73604sePDenLUm5xuhslLzzy/7FUyUnn3L/3XV44d+ZfP3bdjfLQxOTofrn3zkQO10cGVLG3UB5NupLX5x899dvsllz72vSfe8973Te7a/7a77r76httGt89Iym+98+dePvHq4VcPzezZOzo8knbaRqZUZtz3qZYmibjv7rl431tuv+2W236GkfYzT73u9BIe9qoYOXHM4qh17Fhy9owns2b3zMq52VI50FJJkVZLLk0FqILhD2GslEpkBgC+X0Ym9Vxf6W4/f4pMrK6tdJu9y6+8AhCkaeq4LgJmNatXB2q1gT/9yP+9MNdJ4mxkZKxaHTh29I1KuUYpnR5iWvaSqJfE68Ojo398728kH/y1UydP/9XHPi7isMEhNardaYo0c70SdxudjHkCN6oVi1CqbFOQlirVyqN0fY3mbwywQUoW/epi5Fwhrx8itZHGKmVkv1zC2cYh5YYwWl9xQkO8Gkqx5ZqYOkxJpJRoORo8NrBvpmx377v9DsjsQ5/9/LmnnqxWR5lDA15yqfvWt/6HqalJq7LA5XlsAW0ISowNatVzZ8586v774/83/PIjD03u3fsPD/xzY2o7Afj2Q4+8651vv/XOtwu7UkOGcveqmV3bR4Z0L2n25vy1tdrMTkyKdn3WK7n0trdee8O1l/zu7849/tXHX3zuiWMvnqk24J3vef/O3Xtz8EnpqooSoWbPLj/7wgsYQPaW0Yfff/OKZKu4lALN4q6OQ62IVCROWkmWrK6t7pzeCZDb7NjxY9zlt11z3cL84lqzOTg8XBsYWlhaCfxymCS//6t/eO111zz51JOlwLt0/yXHjx+99S03j4yMBG4Gdj1NTwnR8lgZ2zLBZSvM3PHFD/3mB9bPLa1kcr3C/Knpo68cnxge4qUxhFC9VvWp8UnspMsVFM1Mb690pJHSJZyiPM0SRgtOMUm9To7VipOTQqprQ8rBs0EB5EiBvCku+utFJYz6j2NCrLUEs2K3Rlzx3v+8Y+eexdm5tcV1hwXf/+53n/zOd4TWGrPK6MxvfuzPP33fP6wffu63fuX9KYizMvzoJz5nalTWa7XSwHh5aO307Pzi/K4903y4fOTo8eHG6NKJswWPjgQD+/Zti1TmpGEQxh/78EdHOAmMqFc8JGNSDhrjY3xsCAikVhQjVE4eH7xBsbSwuryURNHE+JQXFBmJc+BVyDTUh5vL61/50ue/dP9fUx40dCfJpM6Q7RNyKiXDnjBIMcoYY2AhE9l6a91mdvue7dTxtbW+7+eVVZINOo6DjU7DfTt2HnnxpRuvvGxiauS/vP99991/387pisiaYCHprbgec3kdBFGZiNMla/X2i2Z+6QO/9s9//VdLp4WQYm75+DUXTYilVeINaWSZSJDGkiBMGoud9Jpd1//yf/rlsNVCBhzA1ljKcgSbSeEVxOkbSldb83eQO2jBooE2VQnRBg+J2pg9Vf0OVUH3BNYuzZ9Ll9omFD6462tnXv/Oo+OTU+7IyOtP/yBenb941/b/9ju//eyD9amxSeLTcZ/V8ecUtU0RTw3tWT07j4i94vJLBKjZc8c9F+KsOzozyjGdnzuLiGmn6w5tJMhLsbn/q//20T/6w2RtAZIsYK5Ya2apqPZ6tZFht1oCAUZkwJAMZ7FDJmambJYgxmWv1Wm1lExHh3YBL8XzUWN44j++755HPvcJmgktVTEGTzExxOQ3K3Mc1AnX+5ppxphOt9PpdmhAx0bHwjQ1QFzXYwAlTlyJsE4Hhht//ie/8//8w99Z6C2eW/jaN/6pVOa91nGeIxOPKak6SZbEnJQYC0Bl1GMaqXfefffkwMhD33xw2UY/OvJi+/X5EQxzzdfqZWpdTzAiOae18tLZ9V27Lzv46jNri4tEW4dQpC0hRFmttXFRtbCFVEW7x0DxqNGscLI+v68p/qe1MsYQoTd+t9pYszFJg6DTOXvdtbc2yo1Dh1+JM2GGPTpUvvdv/gol7eXl6Oz8Gw/+2xfVudPx7p3HDh0h2yemBuiK0a7nqqSHRUaU6q6vjU5PVqKgF4dRrz26Y6i5ukoRHhscbLbXsnR1aPvUbDd6+NDh5v/18b/9+McD0N1zcx5328123IlsYhvjGCoBZsTYvGguto2xzizOEqLJYGM498JeCBYoDUDEKumCANqLUmUwYUQhK0SSxBFol5CguEENGCKlabd7KjG1Rr0cVOeXVwGzwGdV6lRdb/b0ieXTKzO7RndfNX3mzHOXXbZ/fHI7Y9KYqOQbxCE8sbC6epZhXKkMsmo5Xm6eOfXyyHjd3TvEVPZPn/qLPZdf86FP3Lc8N1uxNF1YPT03F4lkbm7eMlYeHF5vNT/+0b98/fDhi3dN6o7ijutzz0qFNWYAlNGWRdJYoXSWZ8j8huu7F6DM2vOKPZt7gsa1ROkcCBSjswRh25cmG/UGq341DGNaC1ZOzr94aGmiuaTCUNleBunUrkvuuufnv/+FB2jgXHrddXG1JDtqfNfQyCVXLK03lzvNuJNShufml4hLA7/CfWd5dj2K490zezq9VaRYzS2tLa8r6qJB75lDJ//Lb/7XT3/i76Z37lXN00gBZCZcbotQBiOD/tgIKXFsrUwzMBgbjgs+xtzPhAYKoFKnVEt77cWzc9PTVUr89oTbKCWqp1w9MHPWrGXKzM7PV52qSlKrVNKNSaYcYy7fuZMLOdRLpRDR8hurYSyG0NXX3XTHx/7gymuvH9xeY5xpmWmZWUwJqwDw5dk5Q7KxXfvyKIoYuNyvVC8KSmeef76antPY6F7nrve8G1QyMlKBLPMGxuv7CvkaWWx14AAUPHL/33SOHytNj7fCUHGpCdZWoQKAKpklvUxJmSRxmiRZmmildNHkjmO61YArTqAK9IKQjjobCiJ2U/O8r7hH8PJk+8AT33viiZcRgAfgddBr33v6mnfec/DVb6ex+6XP3D974vj0zM/MvvLi/ksvX4KyPREvz72UZWlLpNPjk7//kT/+8J/c2xUx9pwsSiLRbQxVesmq0InDnQ5OBRiMjIrS8ph7bD79wP/xe3//yU9fvmO3IUsk6wUMtVaWur3lSaSpX3LcMmtUwCpFCRiBQDNti+0IJgkHq1wXz7/w0L4gocSUuysd65Szbk+aFo8z00t2M9xcWkBKDVBcxgSYEdImraVOGh45+lqtTK6+7pp33X3XzbfeMjYxDqwgcjJxtLLu+b7DAqAMhJ6fOzbYGOLlMhgGhkedqLm80l5d0euttfnltgge+tq/DF10bWV0+vSREwbU0NAgaB24XBvbara1wY4jy5WBK9/+Xsb4q3OrS8tdz/NKWW6MPEkXsKXVTgrPA2O4LqTrTJECadmz53cmCo4phC1Ypoc2m3WFXftdOoQ8yoLtey+6I+B7LwcLA5UakbpF/czBF7/l5rGd03923VVK6WpQOnXkaG+t+Qcf/2gfx4O1teHB3fv2Eo9bh1gBxVZwjoqVUgjjfjPFpEYjHdQqXSGlVEMNZ6kpPvQ//uATf/6RS2Z2pc2VxblTY8OjcRgeeengtt37yuXMZxa8ggCHuEaZ3DeUpanCnCJpQIj1M8eq/iC699f/47lzs5GIzs4eV0qXnNK5kx0HQLnQSUEV5EKMwPZdlUsvv2poZOTym27Zu3fmoj27SMAhCVOR9lXJAykgqBXsZBSsOXH0cK3mDw0PapSJSHveQKaddprJTHINLFOElddaa9t2ThPficMeKxZHC2Jg5ZSroPKrSqFZrZasrRPmWOImSUIppQ7DRcewP5HMckhpN8hZLhyVoedJz+HHNNQYIPgpP4kG3pftyu8C3KcAkAaoAoISkRdRxc6aIpiC7MuCFhWo0oDAUnLg2adue8ftPvMCPxBSZFEvr7sxTpMIEKoCX+yFtEwFGILs9sERruzqueWGC3//yb/fNlCLFs7WecFKIUW7uVarVqrDw95gwxtsQBCA0QXDlIYwkgSxSqW9OPv//fEHtk9O0B8dfu2GW66/8513bNs2GrbXqbRLc4svPf3s2bgZBKWpHTsnJrcNj42NT47XGw3w/WIOD0CmurlMrHadAJSJ2p00DNu9c5FAAtirrx0+N//a7//BB8POslcrSSk8D/Nqo1F1LGYOFOudBpd374UcdSvfa2xyt5g+YVGOqxg2cWKAeSMThRkoq9TgAmpz3Se7U7pfLRSay2hj29WC2pSU3piW2pRk1tZskdkUVkf9DioPSBQLiWzg8URpigkxFhGklcaIAfcEIKWVypTLsOe4OZotNAKZwy2CzKhWLywNj5lOx/Zl1R3aH6AlxdwQBTJY8jICLsOJFGfXlqvcZcPlE0u9X/jV3/vbP/vw22+8bv71wxB3hhv12sBw2lkyyyqViTbKr9aw5xeELATKGLSEsj/7zOsIgwZByyPBf//Yh5iDwvby8Mh20Ysnxoevvf1mKHvFzhbf2DkAq4VQ7Yj7vkpiqhWxCFJtFuZb6+3WSrM2Pra22v7Rqye/8MUvv3Hy5N/d/9cQBOli6FYDrXMAWIgdMcA804CNsQ62BpJuXPJ9bUFLxV2SpsZhNE2lRUABW8fPigag1JJbSRkraoC+UBcyBT4hxUSpOc/1s+lsyIFNe16gdY0QFmiLdWdTRB3AphoxTrA1QglrFCaOLVRPGXOUNg5lUimOme9zkQqNrVCSc66E1NYU1SPjgZdaTY3NhOjvuUmwxNg+KfRaq1epVhjSUhuPOhoxAagTh7aMtcH//d4/W/7tX7/zlpsirZc6oedQv1rPE4RQaScmiHuI50GCUcAIuw4w/Mrhp0qDEGYtGkdHrO6AIdwlYRoyisnwIDQ7QhTzRmlUSANRAJIXw8TatRY1AGncXFhcOnPOwe7Y+LaZXfvfWFl6/oVXPvOZB06urjMGV153Y7betBoRRLFFVvbJXazGG6u5SmuHENfztDEEYe7SVBpDiVSKOpQyVKzp2kLP0HjUy98JaG3zqscW9QPNcc0G0STqC0b2I2ZfF74vBW83ZpY3eL5MDij6RIf9wGvMxkSjFKJUKillpJIBY5SAUCr3YE2oRSYRGAoKGgIcEyM1RgQjjDHFmIj8LjeEOEaBAgNS5LUMaCtSjDHk4B6CRiXNP1Je7cdxhh3sBSVhtFQ2iqWw8Bef/MyRl195793vapSqzfZKyTEUcDV/tynoLgbKSj7mjnE94rDV2ZNnXnthygclFL1235QDkY60UZYjyrwA0kSoFFuGETFaE0pBY0gysBjSLFlYXVteXF6Yy9J0ZGL7tj0XJ5l65sWXvvLg1w88feBoT2ybHj8xu6DA8nLj1HMHhqanrLIiSmgDEWu0Ug6jQDHFJol6Ja8EgI3SUthCcoFgk2GkjCwiGKX5F61BIe0WPFBF2sOmKPco5NCUAkObzNl4KyMiJIup0w0VZwpb65/W4PMTGxv+WjBqcE8pY5RxiEMwtSr3UEodk5dcGNk8HoIxUqaYUmM15zxLhdUageMxliilpWSEUNfNnVKqTZkeZAq1854yadjzfbfkBYyoLJah6jiei5B2B+u9tS52yDcPPP/CCy+89xfuvuSSvSu9Zp2XgoqvDRGR0E5aqE4ALnanX3js24EBXwNQjw64GYRrxK0RzHLPwzJNItf3pNAYg0wEJQgyHa42TarCbnfl1aOZTJjnjY1tDxrDZ1fXnzp48F++9MUXXpurlYJduydeO3XaYji3OD85Wtu951KgDgYik7T48hjSCgi2SmvIXEYAdNoN3Uo5D60ICa19EPnzpXB5YKxVeVqiGGNR0IdqgxgjyiJrMcbMICst6cdDtEGUWPyef3GF//X13PuxtDi/RwWcQRtRdYPLFxcnl8VYAyMIK1HIZWOutCUOzdKMbkjE2GKTSVuMRCbBGM/3tDK2zxepdC0oGQxSCk0ptsoUa1kWI611imzQaDACaZR4Lve5u9bqaB1jA1LmuV2AE5Td2V77U597cFsDbnnbjdsHxhzDaoAdrWWcBEnZq5RNZAxKn/7Xz+8bJyzR2iTU9jpGCnCkAZLnZ6Hy+kOkDBh0Q66s6a52ltc76612t5sJkZa51j5iXDv87Ora9x599Jtf/9p6x0TbyLoSqruMBht2vTl7ZuX662+Jojf8l2crUzNvLJ2rpgrKZZqmWEogYME1xkhtse+LApJgDW4eNn2T32uOKnBNX+gyd4XCBWnBLkb61YKUTqESdmEW7HthXuadP9YtaNI3wYsGtXX8X4g4400zF1gUWQEayIYcZn51ifozQQWRDdp6NsFaUhwZQSghWlNCkeuy0aEyqUI7djNjpVpNej2VOgZ4ZtoM6zQDIDRwE6097k5OVYUQSdpDAC4lqYozAFujysKrsT78lWcunq5dPbP47tvfXiJ4/vTSLlqaGRkxvvPNz98/1YDA04pApCk1qreytjQ6tjNaj8v1cbnWZrSSxiFNezLJZJisnV1cXVgqiMoIIrS9HvJKUHLLs+cWv/3d7zz+/adjA8EAa0qlEg2E2KiNGHns0Uf/0/veiyh7/HuP7b8Ou+Vh4F6WJIQyi4zJhHVwv3u5xTq41c/s/37hg/DjaOXCF174qq1HftKiG51StClNYTYuDsYiOF/jbxE+4o1r2jfXKlvDj7ZPjWE2AkDxz9oYnfu/zREpYz7yCXDHIGaAUhLHMbGQAwqtHcfxGPd937akyr85ZbSBHDhQC1Yxzcrktdn2ydlnDh589p6773nfe3/B5XQddO/Uq2+88vhoGUxBHsiYohRnrdWzo0mCkGdiYJWx3pnTmKBwaX19aTlLMtCW8lImZJxmlhinOuj6pWNvzD78rYef/dFrKQVcdlsWskwSlwflgYiEPsKP/eCxVw4dgjR++HuPz/XsVbfdOeG4OsmYQ7XWfV6ZC81woQG2jLfRN7ngCT9pY/hJLHrBRX7yQbzJSou2/t6Qe0UX0KAitBWT81ddOHBsN8dTN5/YZ0cp/FNqZe0G5yRilDMXgXExZdoSSnFR/PiMF6Da5o6LUIOOZiKznU5btnWqtNGYUUK4zCQa80Qvea1rV7714KGFc1imJssa86/fdNMMMT3CAcm8DqcehWOHDlx007tLpTKEGLoJTmwUt5ZOLawuLUltEKHSWKGNxcQrlWJNnjrw9EPf+NrJTlyuutKaKM684WHbyYhDe+sd1/NUmp5e7v3cu/4DEVZF8K/fP/TLbXHpjbcDwUIYjBFl3GzKvPx7DvSTBvipZnuTqeBCl7rgDvgxo25wuG1ui24cZWzq9G7ubGxA1s0ku7GQtznMuMFka/rHkbhPzi+V1gRwwTKLCqb4gt8e9ZvvkGNayn3PwSQTQiuVh1C/5AcVzy8Tz293OlKkG4RvGQbXBSu8veN+tXy03SFZNnvs1J0DoKrjaSYMrJkc7A7RWnno6MvPPfutb9xw+y9ma0my2m0vL588emRppWkJ4UFJSiUxQszp9ML2uYUnnz/03JFXBcDo0FDGIE1T1qgKQNumZwgh80srgeMSxvZNT585fXR4uFbzaoeOnDi1tOz6jgaIo4xjJpXG2F74jb8pqG593W9yyjcFxq0nbP35prthy3jnH+nXGfj8mCnaEL7dVLzfuHJu5s2Xm/NKsJvbbtDHSxsHWX19BZvJQlZDFWOvFCzBufEwgDIqN2VfysRSSnUhsIcpVQZRSrhXGnHcSqXei6P1dlt1O9VgMIpTVB5KumnqlmZ27rZZurLanSfto+tquDYFiWesQU6DCmfvjktv+NGLr1xx5bujTvjKwYOnjh9P06SjSafb7krRTHpRfs/o2dm5s2tdBVBy+UCl1lFCWrttYrJaa/TiOI57gevtmd6Zpmm3tb7Sak/suIgACoWh1aqlTBe07QiM4+A4ygujf88Ff2ryu9Cx3uSgFxr7J0Pum3KqwRtKxWhzOrH/elO0d85DWoANCFOQhJlNyNOHRXaD6xNtdWELwG+lMJiy4l0QjBiidkPJ2ipiJKVUaS2EIH2iWpb/aGlzf1QKCPJ8PygF3PdapSCeXd2zb4/GqJfEoiuX55d2bd8xPDx5Yi4ip1fPHT2xbTx/nyfesPT+rzxtXOf4sey14+Hdd95z8NDhuYXFM/PnVrrtueWmcJBwSRLnJSsg8BoB1qg8POy53pDnVsqVOInXV1YAQaPa0HkBgHQmHMdLkkRjvrC+BpixoEy4I6UkYPI6wlhGzztTMYr/YwBky2nelBR/aph9k723nnbha3/MhBuiEwjjLfctYuMWBfQFpNbQbxH0lQ/hx/4TdoOBWm/1YXO4m5eWBGljCrZlnKPsTcFSDJgSpXUiM8dxbIGEcwhTME1LY43SyiCX87JfYY5HK4PlavX0ydOVUjBSGWiHPcCkMTZ29PWjyqlP7r906cxJhNDo7kG6oHZ0mp3yzsFvPfr0d7/2VIm6wbapVWmrw4MY6UjGSmooU1xueMwvUUchFZSrE2NjRumFs3PI2kYp6HU6UlmZZVZBkoTaIDeotKK4PjhsCRYrGmGLrCZFRZgmEWOsz+z8kz70JmPYTRD/7xnmTYbcus6bXns+XG+CzEJZCG85su3vGG5UjHABExxs8YT91MCANp21uEoRXotOUVEyGQlWKANZhpAhhGz8q8L+QispZWKpy3kpCBIphRBRHGNWCGlV+GrUqg9We602ZVgac3Lh7P7LLrs2E63Vpd7a/NpSWpAEnqWvzJ4q3i/1MZFWaZmSuRNKw6XZyO7pvSsnjkA3uuq663Sa+DWvLUId8bpXwsq2m21C8lDQixPsBzyvfnmappgFRdgHFyMkMmuMD6AzBdjJpMbI7e/ZAyrO1hmRSlLCjbEEO6bP40821ncJBqVAaeX6YIQwklnt8ABEGjKHGEuxYZZAkiq/JOPIc31oNQE5WaXsGBlT5BslicZgUlR2nz5ivv7cq7pbFWp1JZK8NMXbAawe3H/RxK9+cHrluPPBv3mkVt3dPZfFMB/gcppMt2eat+10fuN9+6drFmkkNElBUoqQRZQKlUqPBxaQkIYh7Co9JfRQbIA4llpQWUkZH6wxIq/cETVSMIM4Zq4GhpmHsTaKck9KKaQkDDsuD2XW14V2ECqXy812O2RgZEIRkZ3eudeP7dp28Ytq+cZafPnPXs27h40o0V/62VEHlwlw7mBtOxob6gynGX3hmROVsj8+NpE0ZH2gIcMwFL0ojnxc4i4vJh1U0TO1xhpWzBcVLUejtN6YFysWqgrYtjGNegH8y7GZUrrf1xAmQ4gRBoyA0DC3sESpTWKxutK7bP+llNMkCx3sOj42VgqlLGGIcqtUpiMjHc9n0iZuAGEaf/J/fuG//Z//ORaEa4Y4ULdYFiV8oZv+9Ree/eaxnixfEwSDftldXc+qayt3jFZOr5GmLh+H8NBq3Xm99e5rRy9/SwPZ5sKJoXsfiF9tHL1kemTw1mFmIoypxwplJ4Ol0r7va4O0Mi7HxT0n1lZXhocH+95vtcj/UFmRJXOwbw3WWkUYA2Eu59wNcEEhwRghANJqW/SNDYBRJlIx9Usu5yljkCdupJRqtlplNrdrdGpSwPbRKVZaoVCi91y7jVIfWYSZlJYBDgweDbvk5aeO+Y473BgwiChjUpm1211ppNfwPNfr9rpSCsaYlKq/eE2Kc/S8qrWWYMwwdiglhBhrc3fTut/iwmjjPKifs4rtascYTUhu7jASzz3/0sWX7qrVPJfLhYX15ZW1bdsHKQnCSANVgARlHIB1erpUpg6VFpjUUHB+AHUYL3FDsONQJCFKwAtUomJOylAKTkcuG9shJ8ajqB0RDDjdf1H9F2/f+9RDr754ePYX71tK4/RvP3jxHVdse/L1ZK29cM/7vfrF4W/96eB6mzkckEAEqYJ1imDNuMeTJPG8gDBsAA699PL9n/n0UL0solZuP5mBVkgbrC0yhbA1RoWGKxFap2BSxrxymXuex1xOOBAsM5WjBEQM2NQo13ettdzhI0PDDsZWGZFmcZK8cfyVgW3DmiDHEJvOASDqpUcoc0GnJosKgbcRBdZ02OTkhMxSKVKv1uhGcdxL4ihjgVMp1whhWaaKOscIoTh3oVBT0MXP1u5uka7zOlduzIoVrch+QjJGKQ2AjAHGmBAbBBWHXnpFKTM0MIiJopTdcP2VCEGa6nPzs67XeO7g6299y42xsEtLy2mazf3wzE03Xo8IdNrx8VOvaF274aaLtWWu573+xkqZi+NHFy6+uF4v1YijXUyTTGZIwukfQTAAqAHY6bZbSPtpt6viaro4ePXN+LZ31D9878NfP3wJHhr/+qNf+ef73vG273SsDRHUixoR5+DLWE5BSo0Q0doSgp547MDHP/6nPzjw+NTEoIq6yGpkgGqgKAeurL/IaCxm2BArKMbaZlomSVuoHkE+Mi52GDHWaFDWSq1B66ZoyzSz1vquS4wVqQBtMMaBMQcPHh6fqMt9hgBYbbHVkZbrSkfa9CXjJcWYEneoVmsvL4XdFiZUaAWAXR5Ug2q5VBJC9IcJkjTVxnDHye87pa3SoC0BzBDBgKw2SkiplMhLEm2NvXCnrH8OUJCZ9I0NUsALLxzcvfsipSEVytg+xwl8//GnmBMMDtdXV+TD33z9wBOvM8Z37JqMYvvgvz3/5JMH4xiuvOLGb3ztqTgESkunT3fmZjv1IZ9xjC0NaNkRCBvYM0j+6O59f/nL+37v+uFS2MRClQliDvhVR2NFWHznFTsWF8hDx7W9eVLvn3gxnDl6on7LzzBizmAAgikhngJXO1QxYIy6rhtHyQ++f+Cv/uIvDxx4fOfEkEuQzRRWloFlBCgrFjhpn/7bglZGKasVyVMPGKHDnpBJL+w0k16HGE2t0WlqhUTaJllW7NtCp9ddXFxcW11Jswwj5PCSSsTy/EKYRIQVNKrK4xio1RGgvryIh3IT0PUzc+2k7W+fAgpRlqVhWAmCgdogQjRNekpaW5y8O46DESMYG620zv9vi9q1v0mstc6MloUJzxdhRaWcp0rUpz8whDBKCCZQq9ZPnpifnq4JlT9y6tQiAH/tyKnrr72RUbj6ymv/+YFHBwbdPfuGGMFXXXnVFx940kIPmXM7tu+58dobpTQu5c313qmTC2//2e03Xnd1yTFQ8IHpWEWdpsug4qW+A9JGwEuhVa0sTrDIqCLxSpmMqBSGq40FpqDdGx0qLYfHaxXPM8rolCNH5+UHQUVXQluIw/B/PfD5+z71P189emj3+IRWocriIP9cCEh+B6o8CZqkr3jloExZJa0iABQzhsEA00ZFLQnGyDJFmDDuQA7eMSakVitxFxBSQuKRPMTFYRzG8UiptONtN4yuvIT1WpoWYvuGlQ0razpAeIPQKgAHjSjGS2fPMozGhkeiOGpHYSylz91yuZqmaRTHpjCV57q+5xlj+tzCZmMBXCqtTRFUlVRxmuok0VpvnerZDXFV0NoyVgwl0AL3GLjxphu//NVvLS4Lykic6h8efOnIaycGh8ZfPnw8B83U7N8/1Wjws3NnC6SQ7t03es/d7xiqVz772Yd/7q5LGzW8uDi7Z+fk0uL88aOLVoOSBmSWf8oSXVfep793/B8fXv/6j0RGRg1ttLKS0XUTVR3ZYKXglRMLO8Z9v3OGHkOT8dDyS2tX7pp87lBb6oYBVyiRpRkGwQr2RbDoxRdf/MiH7j189JV9UzswGJ0lRGlOHEyYBSYsShD0EHQwtB2ICEoJzoo1O20KhUBDXOQomWGhbBbLNAFtfO6WfN9zeF47FgVPqRQMDgwwxuIkkVJePFR625UTN103OuQvlzGUCeBU9hKRFGo3HrIca0uQZti6AEPDo0EQrKyvU0IG6vVKueJgEsf5hXKEUgzCuq6rtGKMMoQLqS0DUoPSyNg8k2sji+GyAr3A+SUH6B8WSUJIcTGTZXkI2bt36rd/8ze+/OVvvPzyyW43HB2e2H/p5Xe/+85eLzl7ZiHLWj//81e85+5b47B38vhpo9K73nWFH8DMzGivu/qlLz+mtXrrW64yOvqd3/rFg88eP3WsHYYdcFEiIwFQm9pzruMfTcqvJxoGGMCqRavDZVM13SBrRiu9L30/7qxmn/7IrXeOvDgUPfHI39ySNJcfPNAVMIoIEIYxyRxsKBhQgDF65JFvdaPu3m17WuvNsNOSieCuSxlHmFiCDMYC2YyhjKPUhZ40AowhWCGkpE1infSyLBJI5/gaAVKZQNq4Di+5nuc4Lue4WOMNPN+CXVpZzYS4aM8eP22Z5skqOomScyULZQY00FTlMFemYdPJv9sR8LwOJasEdg2OJ4njqpKR3cGRGq8GIeYWIE2FUioIfLAgVOpwmmaxZVhzKh0i0xQh6hAMJLdgGWUZGJvGoGX/iCYv8Itzd0qpUpYSVmhNGIStkHD1ldWfueJ/S+IEAN568+V9ioufv+tqrQxzsJLW4+gdP/sWhEBp0+t1Hnvi5TvuuPV3fvd//7evffvFFw6/7fYrlcld/dfffweI3F9UlHilck9D1lkBMZ4JB6ql/KK9ehtVXzrpz/cWb73i5gc/KX/lQ6tv/5PZez8w9se/W+PCnlkxv/JHizXKUUpkCq4rC8FQt98WmDu79vRzL/t+JdNhnHSGqkQrYDavzYMAuZj0YmUtxpzGxqx3VInnH5EiwiUAIoqCBivy2istIUtlgtNEqSwEnfrlttJBKh3fDUqlZqfdbbUHqwM7L5oYqNYeP/CDt43dOLl9moazwGEZgCKUB2FrFVhgDhcSpUmUZ1wLnPPlbq8bho2SE/i+67oL682sYKpwXZf3CVCM7m+lRFmmlMJF60HnWZAVH9VstVr6jmg3GZwu7MVsbuxCsepgMUb91dYtOcOCyhBvHSP0e9QYY4exE8ePXn3V/sr2+sV7ZyrVoNhAM5Tgnmy5QQkKlXIwqS/cd+2kp19+gvFrzjXfsFUXfN5rr3/o8e/dtZ8bD2aG94/Dt2Qs7/3DJ8et2TEw+cOTzdLY2MzE7I1XXl3moFJFXT9TOo7y7PrQw4/Mzp3Nayrs1Wtl14M47SWhGmlQkWqZaeYQKWy3LayLxgZrIuwga5nVhTKwQhYDtv1RPGwhLwY1MkYwYx3AZYcGiHbDKOx0iePWK9V6pd6NwoX5hdHhsYGBUYKWAg+wBM7ydFjAwUIIHbmYMi4RKpUr+y7fxxxn7ex8yffHRxuE0EyIXhgSpYpZTlbUdrboPVmldY4tC3vgvJ4AYzRDhAEW9sIG2Hmeyf5ZwUaZUVj5woOE3FP7ClvFGn5/ZreY0rZFb7MYW8LIcZz3veeuteVFmYbbJkbrjZLWxmoprMZQUUCUVZQ4YKDEzf/4tavueftkkFBEB4nnpgIj2JOGlw4POHUCjWH93U9dbk2Nkkt9D8JQIGaQE9Lg4gaHPDUgRxumDMwvNr/0wFf/5aEvZGE4NFhXMlSZSIhhPmEImmvK4cAcZvPsrz0fpYC63U4JW0qAaUuKYyqNFMZIgkUWc0QJydEFaEOt9RgNHM8moTC4UR/gQTkW6dE3TmZptm/nrt6CEqnt6K4PkCSQWaDGSIsLGX0bE+paTTAmxpiB4ZHVKLLW1mo1zrmSmbbC5a5IQyjSmLHWYYxQ0u8HQjFxRghhiFjIb6tCkw31bXzeC2Gz3w9bPcYL/kKAC2sp3WeOIf2jA2P6ztrfTuqf2FmalyV0ZteE0ROUFT05oaXMvMCTQjAgMrHYpdpSocAKRd1sz7Sfgo9BYFCgbTHRVwWAVERu5m6b4CALX3e7bjn1nOEiaPRAURCUuJ4qBA7b8eqXvvr5Y8d/ODw0ppUGkUe8LO4ZYqlH/TolOYw0aSoNwa7vYmNEKjxabK2iYroDQBpEsKUIoq5RWlmU5xlrBIp72A0oUC3UcL2hKT49eyZDZrgxzCl1Pa9nB1Z7yPXoSGVEs2Xw8y/BjZI2IpYYrUSMaINzr5vIOAxbka7X6obSXhgGriOESIUUWZYbmeTBlxBCKcrjJ8Y5ADXaqhyKshzH6IJ41qILe9YXzI1t1Rib/Ewbrmr6i2QFAsJb/bniF9wfZyrsmcfwfktPacqJyrTSyvU4WKozyRgFHRpQFrnIuIwAcqjRVOjYWIKwR/oEiKlWxoBjXU41JVlKPOYkAii44ATtFLhDTGoD5kFf+wlbgiT3IkNPDg4OVsp+c3kRI1GpVDPIwCjC3EzILMzyXOBzpXSnmSgG5ZqLZIr6umeF/HPR8c4xEaWUEoqIMaCEtTZNIOxwZcdGR8MsPX1mbnF21hkd3jU5jS2EURhMzCx1ZAkz6dSM28lIjRLCEUJSRBjAEgN5LjNrywta6067pYjPXR54fjnw1lo9rTRBmDsOpYQxBgiSNJVS4kJqFpm8qDBKCoQcgjFDffbDTclOC1tkFRt6rmirc7rhmwBKSkopLiBPv+labA8WpsXnzzT6jbqi3ERpLB2XgTVS6v72RJopRbllvjHWw0ZFIbEGu45WglMPE42xTVOtkasxcTmshK0BxH2vai1iFFDqyERxLihupt5oqrWbl4JYxOL47Nnnnj5e5ftPrz8+UPamtk2sr86vLK64Th7gl5Ne3ePC2DSxJEo9F1UaboZMlmX9U2MNCBWUaZrkt6ICTBxXIquRLpibQIrYdEkSZVGatqJOK47K4+PlUml5abnmBQP1upJ6ZWl1gne0LiHE4jCiUiZ+MGRBuEXbP06yVIfrq4uuPzo65q0V8upK606n0+70kgyNVMpF2V7QwgKVUmZCcMaYw0xmGCBpCx38PmvlxoHNBr2rveDg1PSPwoujtOIMtr/OCbSYHe3zP+PiZ+NwT+tCtZP0bwe0aULXdzqdhCFmi34rApJmGSWEEmosYAUYGUI1ct3FGFO/BjaxuSMw6XCsoOxA2GoO1zig5bVulfiuzxUTNvBynNDNRtsE6vm1EqREya8uzTW//MC3Tx1d3Tuzc2H+TKPeKLghkBu4YBSWutXJ7nznnXt2712cn3v+qQOzC03Hh2AgSDsRw4goqwspfm2QwkgqLZOsaMcZhSxiQPO7NcPWrndlY2DAG6gT6hipHSA17qftnkkXJ30+XieuaSHdCyz+/wMAAP//rQfxSuc8IcoAAAAASUVORK5CYII=`
+
+func TestDecodeConfig(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+	for _, f := range []Format{JPEG, PNG, GIF, TIFF, BMP, WEBP} {
+		var buf bytes.Buffer
+		err := f.Encode(&buf, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := f.DecodeConfig(&buf)
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		if cfg.Width != 40 || cfg.Height != 30 {
+			t.Errorf("%s: got %dx%d, want 40x30", f, cfg.Width, cfg.Height)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFS is a file system that images can be saved to.
+type WriteFS interface {
+	fs.FS
+	Create(name string) (io.WriteCloser, error)
+}
+
+// dirFS is a WriteFS backed by the operating system. Unlike os.DirFS, the
+// empty dirFS accepts any path, so it is used when no file system is given.
+type dirFS string
+
+var osFS = dirFS("")
+
+// DirFS returns a WriteFS for the tree of files rooted at dir. As with
+// os.DirFS, names must satisfy fs.ValidPath, so they can't leave dir.
+func DirFS(dir string) WriteFS {
+	return dirFS(dir)
+}
+
+func (dir dirFS) Open(name string) (fs.File, error) {
+	full, err := dir.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(full)
+}
+
+func (dir dirFS) Create(name string) (io.WriteCloser, error) {
+	full, err := dir.join("create", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Create(full)
+}
+
+func (dir dirFS) join(op, name string) (string, error) {
+	if dir == "" {
+		return name, nil
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(string(dir), filepath.FromSlash(name)), nil
+}
+
+// OpenFS reads the pixels and metadata of the named image from fsys.
+func OpenFS(fsys fs.FS, name string, opts ...DecodeOption) (*Img, error) {
+	img, err := New(name)
+	if err != nil {
+		return nil, err
+	}
+	img.fsys = fsys
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	err = img.decode(b, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// OpenAllFS loads images from files in fsys.
+func OpenAllFS(fsys fs.FS, files []string, opts ...DecodeOption) ([]image.Image, error) {
+	imgs := make([]image.Image, len(files))
+	for i, file := range files {
+		img, err := openFS(fsys, file, opts...)
+		if err != nil {
+			return imgs, err
+		}
+		imgs[i] = img
+	}
+	return imgs, nil
+}
+
+func openFS(fsys fs.FS, file string, opts ...DecodeOption) (image.Image, error) {
+	imgFmt, err := FormatFromFilename(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fsys.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return imgFmt.Decode(f, opts...)
+}
+
+// readSeeker returns f as an io.ReadSeeker, reading it into memory if the file
+// system doesn't support seeking, as with zip archives.
+func readSeeker(f fs.File) (io.ReadSeeker, error) {
+	if rs, ok := f.(io.ReadSeeker); ok {
+		return rs, nil
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// replaceFile replaces the named file, or the file a symlink points to, with
+// b. The data is written to a temporary file in the same directory with the
+// original's mode, which is then renamed over it.
+func replaceFile(name string, b []byte) error {
+	name, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, info.Mode().Perm())
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// writeFile creates name in fsys with the contents b. A file that already
+// exists on disk is replaced with replaceFile, so that it isn't lost if the
+// write fails.
+func writeFile(fsys WriteFS, name string, b []byte) error {
+	if dir, ok := fsys.(dirFS); ok {
+		full, err := dir.join("create", name)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(full); err == nil {
+			return replaceFile(full, b)
+		}
+	}
+	f, err := fsys.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package img
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestOpenFS(t *testing.T) {
+	c := qt.New(t)
+	data, err := FromImage(image.NewNRGBA(image.Rect(0, 0, 12, 8)), PNG).Bytes()
+	c.Assert(err, qt.IsNil)
+
+	fsys := fstest.MapFS{
+		"pages/001.png": {Data: data},
+	}
+	i, err := OpenFS(fsys, "pages/001.png")
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Identifier, qt.Equals, "pages/001.png")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("001.png")
+	c.Assert(err, qt.IsNil)
+	_, err = w.Write(data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(zw.Close(), qt.IsNil)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, qt.IsNil)
+	imgs, err := OpenAllFS(zr, []string{"001.png"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(imgs[0].Bounds().Dx(), qt.Equals, 12)
+}
+
+func TestSaveFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := DirFS(t.TempDir())
+	i := FromImage(image.NewNRGBA(image.Rect(0, 0, 12, 8)), PNG)
+	err := i.SaveAsFS(fsys, "out.jpg")
+	c.Assert(err, qt.IsNil)
+
+	imgs, err := OpenAllFS(fsys, []string{"out.jpg"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(imgs[0].Bounds().Dy(), qt.Equals, 8)
+
+	for _, name := range []string{"../out.jpg", "/out.jpg", "a/../out.jpg"} {
+		_, err = fsys.Create(name)
+		c.Assert(err, qt.ErrorIs, fs.ErrInvalid)
+		_, err = fsys.Open(name)
+		c.Assert(err, qt.ErrorIs, fs.ErrInvalid)
+	}
+}
+
+func TestSaveInPlace(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "photo.jpg")
+	data, err := FromImage(image.NewNRGBA(image.Rect(0, 0, 12, 8)), JPEG).Bytes()
+	c.Assert(err, qt.IsNil)
+	c.Assert(os.WriteFile(name, data, 0600), qt.IsNil)
+	link := filepath.Join(dir, "link.jpg")
+	c.Assert(os.Symlink("photo.jpg", link), qt.IsNil)
+
+	// the file a symlink points to is replaced, keeping its mode, whether
+	// the metadata is spliced in or the pixels re-encoded
+	for _, opts := range [][]EncodeOption{nil, {Quality(50)}} {
+		i, err := Open(link, true)
+		c.Assert(err, qt.IsNil)
+		i.SetTitle("Saved")
+		c.Assert(i.Save(opts...), qt.IsNil)
+
+		info, err := os.Lstat(link)
+		c.Assert(err, qt.IsNil)
+		c.Assert(info.Mode()&fs.ModeSymlink, qt.Not(qt.Equals), fs.FileMode(0))
+		info, err = os.Stat(name)
+		c.Assert(err, qt.IsNil)
+		c.Assert(info.Mode().Perm(), qt.Equals, fs.FileMode(0600))
+		entries, err := os.ReadDir(dir)
+		c.Assert(err, qt.IsNil)
+		c.Assert(entries, qt.HasLen, 2)
+		i, err = Open(name, true)
+		c.Assert(err, qt.IsNil)
+		c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Saved"})
+	}
+}
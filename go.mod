@@ -6,7 +6,10 @@ require (
 	github.com/HugoSmits86/nativewebp v1.2.1
 	github.com/bep/imagemeta v0.12.0
 	github.com/evanoberholster/imagemeta v0.3.1
+	github.com/goccy/go-yaml v1.19.1
 	github.com/hhrutter/tiff v1.0.2
+	github.com/pdfcpu/pdfcpu v0.11.1
+	github.com/pelletier/go-toml v1.9.4
 	github.com/samber/lo v1.52.0
 	github.com/spf13/cast v1.10.0
 	github.com/sunshineplan/pdf v1.0.8
@@ -19,7 +22,6 @@ require (
 	github.com/frankban/quicktest v1.14.6 // indirect
 	github.com/fsnotify/fsnotify v1.5.1 // indirect
 	github.com/gen2brain/webp v0.5.5 // indirect
-	github.com/goccy/go-yaml v1.19.1 // indirect
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/hhrutter/lzw v1.0.0 // indirect
@@ -33,8 +35,6 @@ require (
 	github.com/mattn/go-runewidth v0.0.19 // indirect
 	github.com/mitchellh/mapstructure v1.4.3 // indirect
 	github.com/ohzqq/imgconv v0.0.0-20250610163936-ef40d763b932 // indirect
-	github.com/pdfcpu/pdfcpu v0.11.1 // indirect
-	github.com/pelletier/go-toml v1.9.4 // indirect
 	github.com/philhofer/fwd v1.1.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/rogpeppe/go-internal v1.10.0 // indirect
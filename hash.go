@@ -0,0 +1,238 @@
+package img
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+)
+
+// PerceptualHash holds 64 bit hashes of an image's appearance that stay
+// close when it is resized, recompressed or slightly edited.
+type PerceptualHash struct {
+	// AHash compares an 8x8 thumbnail with its mean.
+	AHash Hash `json:"ahash" yaml:"ahash"`
+	// DHash compares neighbouring pixels of a 9x8 thumbnail.
+	DHash Hash `json:"dhash" yaml:"dhash"`
+	// PHash compares the low frequencies of a 32x32 thumbnail's DCT with
+	// their median. It is the most robust of the three.
+	PHash Hash `json:"phash" yaml:"phash"`
+}
+
+// Hash is a 64 bit perceptual hash, written as 16 hex digits.
+type Hash uint64
+
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(b []byte) error {
+	v, err := strconv.ParseUint(string(b), 16, 64)
+	if err != nil {
+		return err
+	}
+	*h = Hash(v)
+	return nil
+}
+
+// Distance returns the number of bits that differ between h and o.
+func (h Hash) Distance(o Hash) int {
+	return bits.OnesCount64(uint64(h ^ o))
+}
+
+// HashKind selects one of the hashes of a PerceptualHash.
+type HashKind int
+
+const (
+	AHash HashKind = iota
+	DHash
+	PHash
+)
+
+// Get returns the hash of kind k.
+func (p PerceptualHash) Get(k HashKind) Hash {
+	switch k {
+	case AHash:
+		return p.AHash
+	case DHash:
+		return p.DHash
+	}
+	return p.PHash
+}
+
+// PerceptualHash hashes the image's pixels.
+func (img *Img) PerceptualHash() (PerceptualHash, error) {
+	i, err := img.image()
+	if err != nil {
+		return PerceptualHash{}, err
+	}
+	return hashImage(i), nil
+}
+
+func hashImage(i image.Image) PerceptualHash {
+	var p PerceptualHash
+	a := luma(i, 8, 8)
+	mean := 0.0
+	for _, v := range a {
+		mean += v
+	}
+	mean /= 64
+	for k, v := range a {
+		if v > mean {
+			p.AHash |= 1 << k
+		}
+	}
+
+	d := luma(i, 9, 8)
+	for y := range 8 {
+		for x := range 8 {
+			if d[y*9+x] < d[y*9+x+1] {
+				p.DHash |= 1 << (y*8 + x)
+			}
+		}
+	}
+
+	// the top left 8x8 coefficients are the low frequencies. The DC term
+	// is the average brightness, so it isn't hashed and bit 0 is always
+	// unset.
+	c := dct32(luma(i, 32, 32))
+	low := make([]float64, 0, 64)
+	for v := range 8 {
+		for u := range 8 {
+			low = append(low, c[v*32+u])
+		}
+	}
+	sorted := slices.Clone(low[1:])
+	slices.Sort(sorted)
+	// the median of the 63 AC terms
+	median := sorted[31]
+	for k := 1; k < len(low); k++ {
+		if low[k] > median {
+			p.PHash |= 1 << k
+		}
+	}
+	return p
+}
+
+// luma shrinks the image to w x h by averaging the luminance of the pixels
+// that fall in each cell.
+func luma(i image.Image, w, h int) []float64 {
+	b := i.Bounds()
+	sum := make([]float64, w*h)
+	n := make([]float64, w*h)
+	var at func(x, y int) float64
+	switch m := i.(type) {
+	case *image.YCbCr:
+		at = func(x, y int) float64 { return float64(m.Y[m.YOffset(x, y)]) }
+	case *image.Gray:
+		at = func(x, y int) float64 { return float64(m.Pix[m.PixOffset(x, y)]) }
+	default:
+		at = func(x, y int) float64 {
+			r, g, bl, _ := i.At(x, y).RGBA()
+			return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+		}
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			k := cy*w + (x-b.Min.X)*w/b.Dx()
+			sum[k] += at(x, y)
+			n[k]++
+		}
+	}
+	for k := range sum {
+		if n[k] > 0 {
+			sum[k] /= n[k]
+		}
+	}
+	// cells of images smaller than the thumbnail take the nearest pixel
+	for y := range h {
+		for x := range w {
+			if k := y*w + x; n[k] == 0 {
+				sum[k] = at(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h)
+			}
+		}
+	}
+	return sum
+}
+
+var dctCos = func() [32][32]float64 {
+	var c [32][32]float64
+	for k := range 32 {
+		for n := range 32 {
+			c[k][n] = math.Cos(math.Pi / 32 * (float64(n) + 0.5) * float64(k))
+		}
+	}
+	return c
+}()
+
+// dct32 is a separable, unnormalized 2D DCT-II of a 32x32 block.
+func dct32(in []float64) []float64 {
+	tmp := make([]float64, 32*32)
+	for y := range 32 {
+		for u := range 32 {
+			s := 0.0
+			for x := range 32 {
+				s += in[y*32+x] * dctCos[u][x]
+			}
+			tmp[y*32+u] = s
+		}
+	}
+	out := make([]float64, 32*32)
+	for u := range 32 {
+		for v := range 32 {
+			s := 0.0
+			for y := range 32 {
+				s += tmp[y*32+u] * dctCos[v][y]
+			}
+			out[v*32+u] = s
+		}
+	}
+	return out
+}
+
+// GroupDuplicates clusters hashes that are within maxDist bits of another
+// member of the group. Only groups with more than one member are returned,
+// as indices into hashes.
+func GroupDuplicates(hashes []PerceptualHash, kind HashKind, maxDist int) [][]int {
+	parent := make([]int, len(hashes))
+	for k := range parent {
+		parent[k] = k
+	}
+	var find func(int) int
+	find = func(k int) int {
+		if parent[k] != k {
+			parent[k] = find(parent[k])
+		}
+		return parent[k]
+	}
+	for a := range hashes {
+		for b := a + 1; b < len(hashes); b++ {
+			if hashes[a].Get(kind).Distance(hashes[b].Get(kind)) <= maxDist {
+				parent[find(b)] = find(a)
+			}
+		}
+	}
+	groups := map[int][]int{}
+	var roots []int
+	for k := range hashes {
+		r := find(k)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], k)
+	}
+	var out [][]int
+	for _, r := range roots {
+		if len(groups[r]) > 1 {
+			out = append(out, groups[r])
+		}
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"math/bits"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPerceptualHash(t *testing.T) {
+	c := qt.New(t)
+	// the same scene drawn at two sizes
+	scene := func(w, h int, f func(x, y float64) float64) *image.NRGBA {
+		m := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := range h {
+			for x := range w {
+				v := uint8(255 * f(float64(x)/float64(w), float64(y)/float64(h)))
+				m.Set(x, y, color.NRGBA{v, v / 2, 255 - v, 255})
+			}
+		}
+		return m
+	}
+	blobs := func(x, y float64) float64 {
+		return (math.Sin(x*9)*math.Cos(y*7) + 1) / 2
+	}
+	stripes := func(x, y float64) float64 {
+		return (math.Sin((x+y)*25) + 1) / 2
+	}
+	hash := func(m image.Image) PerceptualHash {
+		var buf bytes.Buffer
+		c.Assert(jpeg.Encode(&buf, m, &jpeg.Options{Quality: 60}), qt.IsNil)
+		i, err := FromBytes(buf.Bytes())
+		c.Assert(err, qt.IsNil)
+		h, err := i.PerceptualHash()
+		c.Assert(err, qt.IsNil)
+		return h
+	}
+	hashes := []PerceptualHash{
+		hash(scene(400, 300, blobs)),
+		hash(scene(100, 75, blobs)),
+		hash(scene(400, 300, stripes)),
+		hash(scene(640, 480, blobs)),
+	}
+	for _, k := range []HashKind{AHash, DHash, PHash} {
+		c.Assert(hashes[0].Get(k).Distance(hashes[1].Get(k)) <= 6, qt.IsTrue, qt.Commentf("%v", k))
+		c.Assert(hashes[0].Get(k).Distance(hashes[2].Get(k)) > 16, qt.IsTrue, qt.Commentf("%v", k))
+	}
+	c.Assert(GroupDuplicates(hashes, PHash, 10), qt.DeepEquals, [][]int{{0, 1, 3}})
+	// the DC term isn't hashed and half of the AC terms are above the median
+	for _, h := range hashes {
+		c.Assert(h.PHash&1, qt.Equals, Hash(0))
+		c.Assert(bits.OnesCount64(uint64(h.PHash)), qt.Equals, 31)
+	}
+
+	var h Hash
+	c.Assert(h.UnmarshalText([]byte(hashes[0].PHash.String())), qt.IsNil)
+	c.Assert(h, qt.Equals, hashes[0].PHash)
+}
@@ -0,0 +1,114 @@
+package img
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"path/filepath"
+)
+
+// Path returns the name of the file the image was opened from.
+func (img *Img) Path() string {
+	return img.file
+}
+
+// DocumentID returns the image's xmpMM:DocumentID.
+func (img *Img) DocumentID() string {
+	return img.docID
+}
+
+// SetDocumentID sets xmpMM:DocumentID.
+func (img *Img) SetDocumentID(id string) {
+	img.docID = id
+	img.metaChanged = true
+}
+
+// MetaChanged reports whether the metadata has been changed since it was
+// read, including by giving the image a new document ID.
+func (img *Img) MetaChanged() bool {
+	return img.metaChanged
+}
+
+// setIdentifier sets the Dublin Core identifier according to the decoder's
+// scheme. r holds the encoded image.
+func (img *Img) setIdentifier(dec *Decoder, r io.ReadSeeker) error {
+	var id string
+	switch dec.idScheme {
+	case IDPath:
+		id = img.file
+	case IDRelative:
+		if img.file == "" {
+			break
+		}
+		root := dec.idRoot
+		if root == "" {
+			root = "."
+		}
+		abs, err := filepath.Abs(img.file)
+		if err != nil {
+			return err
+		}
+		root, err = filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil {
+			return err
+		}
+		id = filepath.ToSlash(rel)
+	case IDFileHash:
+		_, err := r.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, r)
+		if err != nil {
+			return err
+		}
+		id = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	case IDPixelHash:
+		i, err := img.image()
+		if err != nil {
+			return err
+		}
+		id = "pixels:sha256:" + pixelHash(i)
+	case IDDocument:
+		if img.docID == "" {
+			img.SetDocumentID(newDocumentID())
+		}
+		id = img.docID
+	}
+	img.xmp.DC.Identifier = id
+	return nil
+}
+
+// pixelHash hashes the dimensions and non-premultiplied RGBA pixels of i,
+// so the same pixels hash alike in any format.
+func pixelHash(i image.Image) string {
+	b := i.Bounds()
+	m, ok := i.(*image.NRGBA)
+	if !ok || b.Min != (image.Point{}) || m.Stride != 4*b.Dx() {
+		m = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(m, m.Bounds(), i, b.Min, draw.Src)
+	}
+	h := sha256.New()
+	binary.Write(h, binary.BigEndian, [2]uint32{uint32(b.Dx()), uint32(b.Dy())})
+	h.Write(m.Pix)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// newDocumentID returns a random version 4 UUID URI.
+func newDocumentID() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
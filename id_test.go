@@ -0,0 +1,53 @@
+package img
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestIdentifier(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "album", "photo.png")
+	c.Assert(os.MkdirAll(filepath.Dir(name), 0755), qt.IsNil)
+	c.Assert(Save(name, image.NewNRGBA(image.Rect(0, 0, 2, 2))), qt.IsNil)
+
+	id := func(opts ...DecodeOption) string {
+		i, err := New(name)
+		c.Assert(err, qt.IsNil)
+		c.Assert(i.ReadMeta(opts...), qt.IsNil)
+		return i.DublinCore().Identifier
+	}
+	c.Assert(id(), qt.Equals, name)
+	// the root only applies to relative ids, in either order
+	c.Assert(id(Identifier(IDRelative), IDRoot(dir)), qt.Equals, "album/photo.png")
+	c.Assert(id(IDRoot(dir), Identifier(IDRelative)), qt.Equals, "album/photo.png")
+	c.Assert(id(IDRoot(dir)), qt.Equals, name)
+
+	fileHash := id(Identifier(IDFileHash))
+	c.Assert(strings.HasPrefix(fileHash, "sha256:"), qt.IsTrue)
+	c.Assert(id(Identifier(IDFileHash), IDRoot(dir)), qt.Equals, fileHash)
+	pixelHash := id(Identifier(IDPixelHash))
+	c.Assert(strings.HasPrefix(pixelHash, "pixels:sha256:"), qt.IsTrue)
+
+	// a document id is created, saved and then read back
+	i, err := New(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.ReadMeta(Identifier(IDDocument)), qt.IsNil)
+	docID := i.DublinCore().Identifier
+	c.Assert(docID, qt.Matches, `uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`)
+	c.Assert(i.Save(), qt.IsNil)
+	c.Assert(id(Identifier(IDDocument)), qt.Equals, docID)
+
+	// editing the metadata changes the file hash but not the pixel hash
+	i.SetTitle("Renamed")
+	c.Assert(i.Save(), qt.IsNil)
+	c.Assert(id(Identifier(IDFileHash)), qt.Not(qt.Equals), fileHash)
+	c.Assert(id(Identifier(IDPixelHash)), qt.Equals, pixelHash)
+	c.Assert(id(Identifier(IDDocument)), qt.Equals, docID)
+}
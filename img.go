@@ -1,13 +1,14 @@
 package img
 
 import (
-	"encoding/xml"
-	"fmt"
+	"bytes"
+	"errors"
 	"image"
 	"io"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/evanoberholster/imagemeta/xmp"
 )
@@ -17,7 +18,16 @@ type Img struct {
 	Fmt      Format
 	img      image.Image
 	file     string
+	fsys     fs.FS
+	data     []byte
 	withMeta bool
+	// tags is the tree read from the keyword fields, or set with SetTags.
+	tags *Tags
+	// metaChanged is set by the metadata setters so that saving embeds an
+	// XMP packet even when it would be empty.
+	metaChanged bool
+	// docID is the xmpMM:DocumentID.
+	docID string
 }
 
 func New(name string) (*Img, error) {
@@ -27,87 +37,346 @@ func New(name string) (*Img, error) {
 	ext := filepath.Ext(name)
 	imgFmt, err := FormatFromExtension(ext)
 	if err != nil {
-		return nil, fmt.Errorf("format error %w", err)
+		return nil, err
 	}
 	img.Fmt = imgFmt
 	return img, nil
 }
 
-func (img *Img) ReadMeta() error {
-	f, err := os.Open(img.file)
+// FromReader reads an encoded image from r, detecting its format from the
+// data. See FromBytes.
+func FromReader(r io.Reader, opts ...DecodeOption) (*Img, error) {
+	dec := NewDecoder(r, opts...)
+	if dec.maxBytes > 0 {
+		r = io.LimitReader(r, dec.maxBytes+1)
+	}
+	b, err := io.ReadAll(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer f.Close()
-	dec := NewDecoder(f)
-	dec.opts.ImageFormat = img.Fmt.metaFmt()
-	x, err := dec.DecodeXMP(f)
+	return FromBytes(b, opts...)
+}
+
+// FromBytes decodes the pixels and metadata of an encoded image held in
+// memory. The format is detected from the data.
+func FromBytes(b []byte, opts ...DecodeOption) (*Img, error) {
+	imgFmt, err := FormatFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	img := &Img{
+		Fmt:  imgFmt,
+		data: b,
+	}
+	err = img.decode(b, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// FromImage wraps an already decoded image to be encoded as f.
+func FromImage(i image.Image, f Format) *Img {
+	img := &Img{
+		Fmt: f,
+		img: i,
+	}
+	img.xmp.DC.Format = f.ImageType()
+	return img
+}
+
+// decode reads the pixels and, if the format supports it, the metadata from
+// the same buffer.
+func (img *Img) decode(b []byte, opts ...DecodeOption) error {
+	i, err := img.Fmt.Decode(bytes.NewReader(b), opts...)
 	if err != nil {
 		return err
 	}
-	img.xmp = x
-	img.xmp.DC.Identifier = img.file
-	img.xmp.DC.Format = img.Fmt.ImageType()
-	return nil
+	img.img = i
+	if !img.Fmt.hasMeta() {
+		img.xmp.DC.Format = img.Fmt.ImageType()
+		return img.setIdentifier(newDecoder(img.Fmt, opts...), bytes.NewReader(b))
+	}
+	return img.readMeta(bytes.NewReader(b), opts...)
 }
 
-func (img *Img) Open() error {
-	f, err := os.Open(img.file)
+// ReadMeta reads the image's metadata. See Sidecar for merging it with an
+// XMP sidecar.
+func (img *Img) ReadMeta(opts ...DecodeOption) error {
+	if img.data != nil {
+		return img.readMeta(bytes.NewReader(img.data), opts...)
+	}
+	f, err := img.filesystem().Open(img.file)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
-	dec := NewDecoder(f)
-	i, err := dec.Decode(img.Fmt)
+	rs, err := readSeeker(f)
 	if err != nil {
 		return err
 	}
-	img.img = i
-	if img.withMeta {
-		err := img.ReadMeta()
+	return img.readMeta(rs, opts...)
+}
+
+func (img *Img) readMeta(r io.ReadSeeker, opts ...DecodeOption) error {
+	var (
+		x    xmp.XMP
+		tags *Tags
+		err  error
+	)
+	dec := newDecoder(img.Fmt, opts...)
+	switch {
+	case !img.Fmt.hasMeta():
+		// only the sidecar and identifier are read
+	case img.Fmt == CBZ:
+		x, err = decodeCBZMeta(r)
+	default:
+		dec.r = r
+		dec.opts.ImageFormat = img.Fmt.metaFmt()
+		x, tags, err = dec.decodeMeta(r)
+	}
+	if err != nil && !errors.Is(err, ErrNoMetadata) {
+		return err
+	}
+	if dec.sidecar != NoSidecar && img.file != "" {
+		sx, stags, err := img.readSidecar()
 		if err != nil {
 			return err
 		}
+		if dec.sidecar == PreferSidecar {
+			x, tags = mergeMeta(sx, stags, x, tags)
+		} else {
+			x, tags = mergeMeta(x, tags, sx, stags)
+		}
 	}
-	return nil
+	img.xmp = x
+	img.tags = tags
+	img.docID = dec.docID
+	img.xmp.DC.Format = img.Fmt.ImageType()
+	return img.setIdentifier(dec, r)
 }
 
-func (img *Img) Save(opts ...EncodeOption) error {
-	if img.img == nil {
-		i, err := open(img.file)
+// Open decodes the image's pixels, and its metadata if it was created with
+// withMeta, checking any limits in opts first.
+func (img *Img) Open(opts ...DecodeOption) error {
+	if !img.withMeta {
+		f, err := img.filesystem().Open(img.file)
 		if err != nil {
 			return err
 		}
+		defer f.Close()
+		i, err := NewDecoder(f, opts...).Decode(img.Fmt)
+		if err != nil {
+			return err
+		}
+		img.img = i
+		return nil
+	}
+	b, err := fs.ReadFile(img.filesystem(), img.file)
+	if err != nil {
+		return err
+	}
+	return img.decode(b, opts...)
+}
+
+// image returns the decoded pixels, decoding them from memory or disk on
+// first use.
+func (img *Img) image() (image.Image, error) {
+	if img.img != nil {
+		return img.img, nil
+	}
+	if img.data != nil {
+		i, err := img.Fmt.Decode(bytes.NewReader(img.data))
+		if err != nil {
+			return nil, err
+		}
 		img.img = i
+		return i, nil
+	}
+	i, err := openFS(img.filesystem(), img.file)
+	if err != nil {
+		return nil, err
+	}
+	img.img = i
+	return i, nil
+}
+
+// filesystem returns the file system the image was opened from.
+func (img *Img) filesystem() fs.FS {
+	if img.fsys == nil {
+		return osFS
 	}
-	enc := NewEncoder(img.Fmt, opts...)
-	return enc.Save(img.file, img.img)
+	return img.fsys
 }
 
+// writeFS returns the file system the image was opened from, which must be a
+// WriteFS to write files next to it.
+func (img *Img) writeFS() (WriteFS, error) {
+	if img.fsys == nil {
+		return osFS, nil
+	}
+	w, ok := img.fsys.(WriteFS)
+	if !ok {
+		return nil, &fs.PathError{Op: "create", Path: img.file, Err: errors.ErrUnsupported}
+	}
+	return w, nil
+}
+
+func (img *Img) Save(opts ...EncodeOption) error {
+	if img.file == "" {
+		return ErrNoFileName
+	}
+	return img.saveFS(osFS, img.file, img.Fmt, opts)
+}
+
+// SaveAs encodes the image to name in the format of its extension. Metadata
+// edited with the setters is embedded for JPEG, PNG, WEBP and TIFF.
 func (img *Img) SaveAs(name string, opts ...EncodeOption) error {
+	return img.SaveAsFS(osFS, name, opts...)
+}
+
+// comicInfo prepends the image's Dublin Core as a ComicInfo option when
+// saving a CBZ, so options passed by the caller take precedence.
+func (img *Img) comicInfo(to Format, opts []EncodeOption) []EncodeOption {
+	if to != CBZ {
+		return opts
+	}
+	return append([]EncodeOption{ComicInfo(img.xmp.DC)}, opts...)
+}
+
+// SaveAsFS is like SaveAs but creates the file in fsys.
+func (img *Img) SaveAsFS(fsys WriteFS, name string, opts ...EncodeOption) error {
 	to, err := FormatFromExtension(filepath.Ext(name))
 	if err != nil {
-		return fmt.Errorf("can't save as format %w", err)
+		return err
+	}
+	return img.saveFS(fsys, name, to, opts)
+}
+
+// saveFS writes the image to name. When it is saved in its own format
+// without encode options, other than StripMeta, the original bytes are
+// reused with any edited metadata spliced in, so the pixels are never
+// re-encoded. A PDF or CBZ is never re-encoded to its own format, which
+// would drop all but the first page.
+func (img *Img) saveFS(fsys WriteFS, name string, to Format, opts []EncodeOption) error {
+	enc := NewEncoder(to, opts...)
+	meta := img
+	if enc.strip != nil {
+		meta = img.keptMeta(*enc.strip)
+	}
+	if to == img.Fmt && enc.metaOpts == len(opts) && (enc.strip == nil || canStripMeta(to)) {
+		if b, ok := img.source(); ok {
+			var err error
+			if enc.strip != nil {
+				b, err = stripMeta(to, b, *enc.strip)
+				if err != nil {
+					return err
+				}
+			}
+			if !meta.metaChanged {
+				return writeFile(fsys, name, b)
+			}
+			if canEmbedMeta(to) {
+				b, err := meta.embedMeta(to, b)
+				if err != nil {
+					return err
+				}
+				return writeFile(fsys, name, b)
+			}
+		}
 	}
-	if img.img == nil {
-		i, err := open(img.file)
+	if _, ok := img.source(); ok && to == img.Fmt && img.Fmt.multiPage() {
+		// re-encoding would keep only the first page
+		return &FormatError{Format: to, Op: "save", Err: ErrMultiPage}
+	}
+	enc = NewEncoder(to, meta.comicInfo(to, opts)...)
+	if !(meta.metaChanged || meta.hasDC()) || !canEmbedXMP(to) {
+		i, err := img.image()
 		if err != nil {
 			return err
 		}
-		img.img = i
+		var buf bytes.Buffer
+		err = enc.Encode(&buf, i)
+		if err != nil {
+			return err
+		}
+		return writeFile(fsys, name, buf.Bytes())
 	}
-	enc := NewEncoder(to, opts...)
-	return enc.Save(name, img.img)
+	b, err := meta.encodeWithMeta(enc)
+	if err != nil {
+		return err
+	}
+	return writeFile(fsys, name, b)
+}
+
+// WriteTo encodes the image to w in its format. It implements io.WriterTo.
+func (img *Img) WriteTo(w io.Writer) (int64, error) {
+	i, err := img.image()
+	if err != nil {
+		return 0, err
+	}
+	cw := &countingWriter{w: w}
+	err = NewEncoder(img.Fmt).Encode(cw, i)
+	return cw.n, err
+}
+
+// Bytes returns the image encoded in its format.
+func (img *Img) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := img.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Config returns the color model and dimensions of the image. If the image
+// hasn't been opened only the file header is read.
+func (img *Img) Config() (image.Config, error) {
+	if img.img != nil {
+		return image.Config{
+			ColorModel: img.img.ColorModel(),
+			Width:      img.img.Bounds().Dx(),
+			Height:     img.img.Bounds().Dy(),
+		}, nil
+	}
+	if img.data != nil {
+		return img.Fmt.DecodeConfig(bytes.NewReader(img.data))
+	}
+	f, err := img.filesystem().Open(img.file)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	return img.Fmt.DecodeConfig(f)
 }
 
 func (dec *Img) DublinCore() xmp.DublinCore {
 	return dec.xmp.DC
 }
 
+// CaptureTime returns when the photo was taken, read from the XMP or the
+// EXIF DateTimeOriginal. It is the zero time if neither is set.
+func (dec *Img) CaptureTime() time.Time {
+	return dec.xmp.Exif.DateTimeOriginal
+}
+
+// GPS returns where the photo was taken in decimal degrees, south and west
+// being negative, read from the XMP or EXIF. ok is false if neither has a
+// location.
+func (dec *Img) GPS() (lat, long float64, ok bool) {
+	lat, long = dec.xmp.Exif.GPSLatitude, dec.xmp.Exif.GPSLongitude
+	return lat, long, lat != 0 || long != 0
+}
+
+// EncodeXMP writes the image's metadata to w as a standalone XMP packet.
 func (dec *Img) EncodeXMP(w io.Writer) error {
-	enc := xml.NewEncoder(w)
-	enc.Indent("", "  ")
-	return enc.Encode(dec.xmp)
+	packet, err := dec.xmpPacket()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(packet)
+	return err
 }
 
 var (
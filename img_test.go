@@ -0,0 +1,35 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestFromBytes(t *testing.T) {
+	c := qt.New(t)
+	src := FromImage(image.NewNRGBA(image.Rect(0, 0, 20, 10)), PNG)
+	data, err := src.Bytes()
+	c.Assert(err, qt.IsNil)
+
+	i, err := FromReader(bytes.NewReader(data))
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.Fmt, qt.Equals, PNG)
+
+	cfg, err := i.Config()
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.Width, qt.Equals, 20)
+	c.Assert(cfg.Height, qt.Equals, 10)
+
+	var buf bytes.Buffer
+	n, err := i.WriteTo(&buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, int64(buf.Len()))
+
+	c.Assert(i.Save(), qt.ErrorIs, ErrNoFileName)
+
+	_, err = FromBytes([]byte("not an image"))
+	c.Assert(err, qt.ErrorIs, ErrUnsupportedFormat)
+}
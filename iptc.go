@@ -0,0 +1,266 @@
+package img
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+)
+
+const (
+	jpegIPTCHeader = "Photoshop 3.0\x00"
+	psResourceIPTC = 0x0404
+	// psResourceIPTCDigest is an MD5 of the IPTC data that Photoshop uses to
+	// spot edits by other programs.
+	psResourceIPTCDigest = 0x0425
+)
+
+// iptcUTF8 is the ISO 2022 escape sequence for UTF-8, written to the 1:90
+// CodedCharacterSet dataset.
+var iptcUTF8 = []byte{0x1b, 0x25, 0x47}
+
+// iptcDataset returns the record 2 dataset that holds the field, if any.
+func (f ExifField) iptcDataset() (uint8, bool) {
+	switch f {
+	case Title:
+		// ObjectName
+		return 5, true
+	case Keywords:
+		return 25, true
+	case Byline:
+		return 80, true
+	case Credit:
+		return 110, true
+	case Copyright:
+		// CopyrightNotice
+		return 116, true
+	case Description:
+		// Caption-Abstract
+		return 120, true
+	}
+	return 0, false
+}
+
+// iptcName returns the name imagemeta gives the field's dataset.
+func (f ExifField) iptcName() string {
+	switch f {
+	case Title:
+		return "ObjectName"
+	case Keywords:
+		return "Keywords"
+	case Byline:
+		return "By-line"
+	case Credit:
+		return "Credit"
+	case Copyright:
+		return "CopyrightNotice"
+	case Description:
+		return "Caption-Abstract"
+	}
+	return ""
+}
+
+var iptcFields = []ExifField{Title, Keywords, Byline, Credit, Copyright, Description}
+
+// iptcProps holds the values of the IPTC datasets read by imagemeta, which
+// has already decoded them according to 1:90.
+type iptcProps map[ExifField][]string
+
+func (p iptcProps) add(tag string, v any) {
+	for _, f := range iptcFields {
+		if f.iptcName() != tag {
+			continue
+		}
+		switch v := v.(type) {
+		case []string:
+			p[f] = append(p[f], v...)
+		case string:
+			if v != "" {
+				p[f] = append(p[f], v)
+			}
+		default:
+			p[f] = append(p[f], fmt.Sprint(v))
+		}
+	}
+}
+
+// meta returns the Dublin Core and flat tags held by the datasets.
+func (p iptcProps) meta() (xmp.XMP, *Tags) {
+	x := xmp.XMP{DC: xmp.DublinCore{}}
+	x.DC.Title = p[Title]
+	x.DC.Description = p[Description]
+	x.DC.Creator = p[Byline]
+	if len(x.DC.Creator) == 0 {
+		x.DC.Creator = p[Credit]
+	}
+	x.DC.Rights = p[Copyright]
+	tags := NewTags()
+	for _, k := range p[Keywords] {
+		tags.Add(k)
+	}
+	x.DC.Subject = tags.StringSlice()
+	return x, tags
+}
+
+// marshalIPTC encodes Dublin Core as IPTC-IIM records, declaring UTF-8.
+func marshalIPTC(dc xmp.DublinCore) ([]byte, error) {
+	var b bytes.Buffer
+	write := func(record, id uint8, v []byte) error {
+		if len(v) > 0x7fff {
+			return fmt.Errorf("iptc dataset %d:%d is too long", record, id)
+		}
+		b.Write([]byte{0x1c, record, id})
+		binary.Write(&b, binary.BigEndian, uint16(len(v)))
+		b.Write(v)
+		return nil
+	}
+	write(1, 90, iptcUTF8)
+	// record version 4
+	write(2, 0, []byte{0, 4})
+	fields := []struct {
+		f    ExifField
+		vals []string
+	}{
+		{Title, first(dc.Title)},
+		{Keywords, dc.Subject},
+		{Byline, dc.Creator},
+		{Credit, first(dc.Creator)},
+		{Copyright, first(dc.Rights)},
+		{Description, first(dc.Description)},
+	}
+	for _, fv := range fields {
+		id, _ := fv.f.iptcDataset()
+		for _, v := range fv.vals {
+			err := write(2, id, []byte(v))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func first(vals []string) []string {
+	if len(vals) == 0 {
+		return nil
+	}
+	return vals[:1]
+}
+
+// psResource is an image resource block of a Photoshop APP13 segment.
+type psResource struct {
+	id   uint16
+	name []byte
+	data []byte
+}
+
+func splitPSResources(data []byte) ([]psResource, error) {
+	var res []psResource
+	bad := func(i int) error {
+		return &CorruptError{Offset: int64(i), Err: errors.New("bad photoshop resource block")}
+	}
+	i := 0
+	for i < len(data) {
+		if i+7 > len(data) || string(data[i:i+4]) != "8BIM" {
+			return nil, bad(i)
+		}
+		r := psResource{id: binary.BigEndian.Uint16(data[i+4:])}
+		// the pascal string name is padded to an even length
+		n := int(data[i+6])
+		nameLen := n + 1
+		if nameLen%2 == 1 {
+			nameLen++
+		}
+		j := i + 6 + nameLen
+		if j+4 > len(data) {
+			return nil, bad(i)
+		}
+		r.name = data[i+6 : j]
+		size := int(binary.BigEndian.Uint32(data[j:]))
+		j += 4
+		if size < 0 || j+size > len(data) {
+			return nil, bad(i)
+		}
+		r.data = data[j : j+size]
+		res = append(res, r)
+		i = j + size + size&1
+	}
+	return res, nil
+}
+
+func joinPSResources(res []psResource) []byte {
+	var b bytes.Buffer
+	for _, r := range res {
+		b.WriteString("8BIM")
+		binary.Write(&b, binary.BigEndian, r.id)
+		if len(r.name) == 0 {
+			b.Write([]byte{0, 0})
+		} else {
+			b.Write(r.name)
+		}
+		binary.Write(&b, binary.BigEndian, uint32(len(r.data)))
+		b.Write(r.data)
+		if len(r.data)&1 == 1 {
+			b.WriteByte(0)
+		}
+	}
+	return b.Bytes()
+}
+
+func isJPEGIPTC(s jpegSegment) bool {
+	return s.marker == 0xed && bytes.HasPrefix(s.data, []byte(jpegIPTCHeader))
+}
+
+// embedJPEGIPTC replaces the IPTC resource of the Photoshop APP13 segment,
+// keeping its other resources and updating the IPTC digest if there is one.
+func embedJPEGIPTC(data, iptc []byte) ([]byte, error) {
+	segs, scan, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	var res []psResource
+	at := -1
+	for i, s := range segs {
+		if isJPEGIPTC(s) {
+			res, err = splitPSResources(s.data[len(jpegIPTCHeader):])
+			if err != nil {
+				return nil, err
+			}
+			at = i
+			break
+		}
+	}
+	digest := md5.Sum(iptc)
+	replaced := false
+	for i := range res {
+		switch res[i].id {
+		case psResourceIPTC:
+			res[i].data = iptc
+			replaced = true
+		case psResourceIPTCDigest:
+			res[i].data = digest[:]
+		}
+	}
+	if !replaced {
+		res = append(res, psResource{id: psResourceIPTC, data: iptc})
+	}
+	payload := append([]byte(jpegIPTCHeader), joinPSResources(res)...)
+	if len(payload)+2 > 0xffff {
+		return nil, errors.New("iptc data too large for a jpeg segment")
+	}
+	seg := jpegSegment{marker: 0xed, data: payload}
+	if at >= 0 {
+		segs[at] = seg
+		return joinJPEG(segs, scan), nil
+	}
+	// after the JFIF, EXIF and XMP segments
+	i := 0
+	for i < len(segs) && (segs[i].marker == 0xe0 || segs[i].marker == 0xe1) {
+		i++
+	}
+	segs = append(segs[:i], append([]jpegSegment{seg}, segs[i:]...)...)
+	return joinJPEG(segs, scan), nil
+}
@@ -0,0 +1,76 @@
+package img
+
+import (
+	"bytes"
+	"crypto/md5"
+	"image"
+	"testing"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+	qt "github.com/frankban/quicktest"
+)
+
+func TestIPTC(t *testing.T) {
+	c := qt.New(t)
+	var buf bytes.Buffer
+	err := NewEncoder(JPEG).Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 8, 8)))
+	c.Assert(err, qt.IsNil)
+	jpg := buf.Bytes()
+
+	// latin-1 records, without 1:90, next to another resource and a digest
+	latin1 := []byte{0x1c, 2, 120, 0, 4, 'C', 'a', 'f', 0xe9}
+	latin1 = append(latin1, 0x1c, 2, 80, 0, 3, 'A', 'n', 'n')
+	app13 := append([]byte(jpegIPTCHeader), joinPSResources([]psResource{
+		{id: 0x03ed, data: make([]byte, 16)},
+		{id: psResourceIPTC, data: latin1},
+		{id: psResourceIPTCDigest, data: make([]byte, 16)},
+	})...)
+	segs, scan, err := splitJPEG(jpg)
+	c.Assert(err, qt.IsNil)
+	data := joinJPEG(append(segs, jpegSegment{marker: 0xed, data: app13}), scan)
+
+	i, err := FromBytes(data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"Café"})
+	c.Assert(i.DublinCore().Creator, qt.DeepEquals, []string{"Ann"})
+
+	iptc, err := marshalIPTC(xmp.DublinCore{
+		Title:   []string{"Ünïcode"},
+		Subject: []string{"a", "b"},
+		Creator: []string{"Bob", "Cy"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(bytes.Contains(iptc, []byte{0x1c, 2, 110, 0, 3, 'B', 'o', 'b'}), qt.IsTrue)
+	data, err = embedJPEGIPTC(data, iptc)
+	c.Assert(err, qt.IsNil)
+	i, err = FromBytes(data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Ünïcode"})
+	c.Assert(i.DublinCore().Creator, qt.DeepEquals, []string{"Bob", "Cy"})
+	c.Assert(i.Tags().Paths(), qt.DeepEquals, [][]string{{"a"}, {"b"}})
+
+	segs, _, err = splitJPEG(data)
+	c.Assert(err, qt.IsNil)
+	var res []psResource
+	for _, s := range segs {
+		if isJPEGIPTC(s) {
+			res, err = splitPSResources(s.data[len(jpegIPTCHeader):])
+			c.Assert(err, qt.IsNil)
+		}
+	}
+	c.Assert(res, qt.HasLen, 3)
+	c.Assert(res[0].id, qt.Equals, uint16(0x03ed))
+	digest := md5.Sum(iptc)
+	c.Assert(res[2].data, qt.DeepEquals, digest[:])
+
+	// the credit is read as the creator when there is no by-line
+	credit, err := marshalIPTC(xmp.DublinCore{Creator: []string{"Agency"}})
+	c.Assert(err, qt.IsNil)
+	credit = bytes.Replace(credit, []byte{0x1c, 2, 80, 0, 6, 'A', 'g', 'e', 'n', 'c', 'y'}, nil, 1)
+	c.Assert(bytes.Contains(credit, []byte{0x1c, 2, 80}), qt.IsFalse)
+	data, err = embedJPEGIPTC(jpg, credit)
+	c.Assert(err, qt.IsNil)
+	i, err = FromBytes(data)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Creator, qt.DeepEquals, []string{"Agency"})
+}
@@ -0,0 +1,192 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func (dec *Decoder) hasLimits() bool {
+	return dec.hasPixelLimits() ||
+		dec.maxFrames > 0 ||
+		dec.maxBytes > 0
+}
+
+func (dec *Decoder) hasPixelLimits() bool {
+	return dec.maxPixels > 0 ||
+		dec.maxWidth > 0 ||
+		dec.maxHeight > 0
+}
+
+// checkLimits checks the input against the decoder's limits before any pixels
+// are allocated, and returns a reader that starts over from the beginning.
+// The whole input is only buffered when its size or frames are limited;
+// otherwise just the bytes read for the header are kept.
+func (dec *Decoder) checkLimits(f Format) (io.Reader, error) {
+	var b []byte
+	r := dec.r
+	if dec.maxBytes > 0 || dec.maxFrames > 0 && f.hasFrames() {
+		if dec.maxBytes > 0 {
+			r = io.LimitReader(r, dec.maxBytes+1)
+		}
+		var err error
+		b, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if dec.maxBytes > 0 && int64(len(b)) > dec.maxBytes {
+			return nil, &LimitError{Limit: "bytes", Max: dec.maxBytes, Got: int64(len(b))}
+		}
+		r = bytes.NewReader(b)
+	}
+
+	if dec.hasPixelLimits() {
+		var head bytes.Buffer
+		cfg, err := f.limitConfig(io.TeeReader(r, &head))
+		if err != nil {
+			return nil, err
+		}
+		err = dec.checkConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		r = io.MultiReader(&head, r)
+	}
+
+	if dec.maxFrames > 0 && f.hasFrames() {
+		n, err := f.countFrames(b)
+		if err != nil {
+			return nil, err
+		}
+		if n > dec.maxFrames {
+			return nil, &LimitError{Limit: "frames", Max: int64(dec.maxFrames), Got: int64(n)}
+		}
+	}
+
+	if b != nil {
+		return bytes.NewReader(b), nil
+	}
+	return r, nil
+}
+
+// pageOptions returns the pixel limits of dec, which DecodeAll checks for
+// each page of a CBZ or PDF.
+func (dec *Decoder) pageOptions() []DecodeOption {
+	return []DecodeOption{MaxPixels(dec.maxPixels), MaxWidth(dec.maxWidth), MaxHeight(dec.maxHeight)}
+}
+
+func (dec *Decoder) checkConfig(cfg image.Config) error {
+	if dec.maxWidth > 0 && cfg.Width > dec.maxWidth {
+		return &LimitError{Limit: "width", Max: int64(dec.maxWidth), Got: int64(cfg.Width)}
+	}
+	if dec.maxHeight > 0 && cfg.Height > dec.maxHeight {
+		return &LimitError{Limit: "height", Max: int64(dec.maxHeight), Got: int64(cfg.Height)}
+	}
+	px := int64(cfg.Width) * int64(cfg.Height)
+	if dec.maxPixels > 0 && px > int64(dec.maxPixels) {
+		return &LimitError{Limit: "pixels", Max: int64(dec.maxPixels), Got: px}
+	}
+	return nil
+}
+
+// limitConfig reads the dimensions of the image that Decode would return.
+// For a PDF that is the first embedded image rather than the page.
+func (f Format) limitConfig(r io.Reader) (image.Config, error) {
+	if f == PDF {
+		return decodePDFConfig(r)
+	}
+	return f.DecodeConfig(r)
+}
+
+// hasFrames reports whether f can hold more than one frame or page.
+func (f Format) hasFrames() bool {
+	return f == GIF || f == WEBP || f.multiPage()
+}
+
+// countFrames returns the number of frames in a GIF or WEBP or the number of
+// pages in a PDF or CBZ. All other formats have a single frame.
+func (f Format) countFrames(b []byte) (int, error) {
+	switch f {
+	case GIF:
+		return countGIFFrames(b)
+	case WEBP:
+		return countWEBPFrames(b)
+	case PDF:
+		return api.PageCount(bytes.NewReader(b), model.NewDefaultConfiguration())
+	case CBZ:
+		return countCBZPages(b)
+	}
+	return 1, nil
+}
+
+var errFrameCount = errors.New("can't count frames")
+
+func frameCountErr(offset int) error {
+	return &CorruptError{Offset: int64(offset), Err: errFrameCount}
+}
+
+// countGIFFrames walks the GIF block structure without decompressing any
+// image data.
+func countGIFFrames(b []byte) (int, error) {
+	if len(b) < 13 {
+		return 0, frameCountErr(len(b))
+	}
+	i := 13
+	if b[10]&0x80 != 0 {
+		i += 3 << (int(b[10]&0x07) + 1)
+	}
+	frames := 0
+	for i < len(b) {
+		switch b[i] {
+		case 0x21:
+			// extension: introducer, label, sub-blocks
+			i += 2
+		case 0x2c:
+			frames++
+			if i+10 > len(b) {
+				return frames, frameCountErr(i)
+			}
+			packed := b[i+9]
+			i += 10
+			if packed&0x80 != 0 {
+				i += 3 << (int(packed&0x07) + 1)
+			}
+			// lzw minimum code size
+			i++
+		case 0x3b:
+			return frames, nil
+		default:
+			return frames, frameCountErr(i)
+		}
+		// skip data sub-blocks
+		for i < len(b) && b[i] != 0 {
+			i += int(b[i]) + 1
+		}
+		i++
+	}
+	return frames, nil
+}
+
+// countWEBPFrames counts the ANMF chunks of an animated WEBP.
+func countWEBPFrames(b []byte) (int, error) {
+	if len(b) < 12 || string(b[0:4]) != "RIFF" || string(b[8:12]) != "WEBP" {
+		return 0, frameCountErr(0)
+	}
+	frames := 0
+	for i := 12; i+8 <= len(b); {
+		size := int(binary.LittleEndian.Uint32(b[i+4 : i+8]))
+		if string(b[i:i+4]) == "ANMF" {
+			frames++
+		}
+		i += 8 + size + size&1
+	}
+	if frames == 0 {
+		return 1, nil
+	}
+	return frames, nil
+}
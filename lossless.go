@@ -0,0 +1,1043 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// Transform is a lossless JPEG operation. Operations are relative to the
+// image as displayed, so the EXIF orientation is applied first and reset
+// to normal.
+type Transform struct {
+	orient orientation
+	crop   image.Rectangle
+}
+
+var (
+	// AutoOrient applies the EXIF orientation to the pixels.
+	AutoOrient = Transform{orient: orientNormal}
+	// Rotate90 rotates clockwise by 90 degrees.
+	Rotate90   = Transform{orient: orientRotate90}
+	Rotate180  = Transform{orient: orientRotate180}
+	Rotate270  = Transform{orient: orientRotate270}
+	FlipH      = Transform{orient: orientFlipH}
+	FlipV      = Transform{orient: orientFlipV}
+	Transpose  = Transform{orient: orientTranspose}
+	Transverse = Transform{orient: orientTransverse}
+)
+
+// Crop returns a Transform that crops to r. The top left corner is moved up
+// and left to the nearest MCU boundary, which is 8 or 16 pixels depending
+// on the chroma subsampling.
+func Crop(r image.Rectangle) Transform {
+	return Transform{orient: orientNormal, crop: r}
+}
+
+// ErrNotLossless is returned for JPEGs that can't be transformed without
+// decoding, such as progressive and arithmetic coded files.
+var ErrNotLossless = errors.New("jpeg can't be transformed losslessly")
+
+// LosslessTransform rotates, flips or crops a JPEG file in place by
+// rearranging its DCT coefficients, so the image isn't recompressed. Edge
+// blocks that can't be moved are trimmed, as jpegtran -trim does. The
+// EXIF orientation is reset and the EXIF dimensions are updated. The file is
+// replaced by renaming a copy over it, so it is never left half written.
+func LosslessTransform(file string, op Transform) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	out, err := transformJPEG(b, op)
+	if err != nil {
+		return err
+	}
+	return replaceFile(file, out)
+}
+
+// orientation is an EXIF orientation: the transform that turns the stored
+// pixels into the displayed image.
+type orientation int
+
+const (
+	orientNormal orientation = iota + 1
+	orientFlipH
+	orientRotate180
+	orientFlipV
+	orientTranspose
+	orientRotate90
+	orientTransverse
+	orientRotate270
+)
+
+// orientMatrix maps each orientation to the matrix that moves a pixel, with
+// x to the right and y down.
+var orientMatrix = map[orientation][4]int{
+	orientNormal:     {1, 0, 0, 1},
+	orientFlipH:      {-1, 0, 0, 1},
+	orientRotate180:  {-1, 0, 0, -1},
+	orientFlipV:      {1, 0, 0, -1},
+	orientTranspose:  {0, 1, 1, 0},
+	orientRotate90:   {0, -1, 1, 0},
+	orientTransverse: {0, -1, -1, 0},
+	orientRotate270:  {0, 1, -1, 0},
+}
+
+// then returns the orientation that applies o and then p.
+func (o orientation) then(p orientation) orientation {
+	a, b := orientMatrix[o], orientMatrix[p]
+	m := [4]int{
+		b[0]*a[0] + b[1]*a[2], b[0]*a[1] + b[1]*a[3],
+		b[2]*a[0] + b[3]*a[2], b[2]*a[1] + b[3]*a[3],
+	}
+	for k, v := range orientMatrix {
+		if v == m {
+			return k
+		}
+	}
+	return orientNormal
+}
+
+func (o orientation) transposed() bool {
+	return orientMatrix[o][1] != 0
+}
+
+// mirrors reports whether the stored x and y axes are reversed.
+func (o orientation) mirrors() (x, y bool) {
+	m := orientMatrix[o]
+	if o.transposed() {
+		return m[2] < 0, m[1] < 0
+	}
+	return m[0] < 0, m[3] < 0
+}
+
+type jpegComp struct {
+	id     byte
+	h, v   int
+	tq     byte
+	bw, bh int
+	blocks [][64]int32
+}
+
+func (c *jpegComp) block(x, y int) *[64]int32 {
+	return &c.blocks[y*c.bw+x]
+}
+
+// jpegCoeffs is a baseline JPEG decoded to its quantized DCT coefficients,
+// stored in natural order.
+type jpegCoeffs struct {
+	sof        byte
+	width      int
+	height     int
+	hmax, vmax int
+	comps      []*jpegComp
+	qt         [4][64]uint16
+	qtPrec     [4]byte
+	qtUsed     [4]bool
+	segs       []jpegSegment
+}
+
+func (j *jpegCoeffs) mcus() (int, int) {
+	return ceilDiv(j.width, 8*j.hmax), ceilDiv(j.height, 8*j.vmax)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func transformJPEG(data []byte, op Transform) ([]byte, error) {
+	j, err := decodeJPEGCoeffs(data)
+	if err != nil {
+		return nil, formatErr(JPEG, "transform", -1, err)
+	}
+	if op.orient == 0 {
+		op.orient = orientNormal
+	}
+	from := exifOrientation(j.segs)
+	if from == orientNormal && op.orient == orientNormal && op.crop.Empty() {
+		return data, nil
+	}
+	o := from.then(op.orient)
+	err = j.orient(o)
+	if err != nil {
+		return nil, formatErr(JPEG, "transform", -1, err)
+	}
+	if !op.crop.Empty() {
+		err = j.cropTo(op.crop)
+		if err != nil {
+			return nil, formatErr(JPEG, "transform", -1, err)
+		}
+	}
+	j.updateMeta()
+	return j.encode(), nil
+}
+
+// orient applies o to the coefficients, trimming the partial MCUs on edges
+// that would move.
+func (j *jpegCoeffs) orient(o orientation) error {
+	if o == orientNormal {
+		return nil
+	}
+	mirrorX, mirrorY := o.mirrors()
+	mx, my := j.mcus()
+	if mirrorX {
+		mx = j.width / (8 * j.hmax)
+		j.width = mx * 8 * j.hmax
+	}
+	if mirrorY {
+		my = j.height / (8 * j.vmax)
+		j.height = my * 8 * j.vmax
+	}
+	if j.width == 0 || j.height == 0 {
+		return errors.New("image is smaller than an MCU")
+	}
+	m := orientMatrix[o]
+	for _, c := range j.comps {
+		bw, bh := mx*c.h, my*c.v
+		nw, nh := bw, bh
+		if o.transposed() {
+			nw, nh = bh, bw
+		}
+		out := make([][64]int32, nw*nh)
+		for y := range bh {
+			for x := range bw {
+				ox, oy := x, y
+				if o.transposed() {
+					ox, oy = y, x
+				}
+				if mirrorX {
+					if o.transposed() {
+						oy = bw - 1 - x
+					} else {
+						ox = bw - 1 - x
+					}
+				}
+				if mirrorY {
+					if o.transposed() {
+						ox = bh - 1 - y
+					} else {
+						oy = bh - 1 - y
+					}
+				}
+				out[oy*nw+ox] = transformBlock(c.block(x, y), m, o.transposed())
+			}
+		}
+		c.blocks, c.bw, c.bh = out, nw, nh
+		if o.transposed() {
+			c.h, c.v = c.v, c.h
+		}
+	}
+	if o.transposed() {
+		j.width, j.height = j.height, j.width
+		j.hmax, j.vmax = j.vmax, j.hmax
+		for i, used := range j.qtUsed {
+			if used {
+				j.qt[i] = transposeBlock(j.qt[i])
+			}
+		}
+	}
+	return nil
+}
+
+// transformBlock moves the coefficients of a block. Mirroring an axis
+// negates its odd frequencies.
+func transformBlock(b *[64]int32, m [4]int, transposed bool) [64]int32 {
+	var out [64]int32
+	negU, negV := m[0] < 0, m[3] < 0
+	if transposed {
+		negU, negV = m[1] < 0, m[2] < 0
+	}
+	for v := range 8 {
+		for u := range 8 {
+			c := b[v*8+u]
+			if transposed {
+				c = b[u*8+v]
+			}
+			if negU && u%2 == 1 {
+				c = -c
+			}
+			if negV && v%2 == 1 {
+				c = -c
+			}
+			out[v*8+u] = c
+		}
+	}
+	return out
+}
+
+func transposeBlock[T any](b [64]T) [64]T {
+	var out [64]T
+	for v := range 8 {
+		for u := range 8 {
+			out[v*8+u] = b[u*8+v]
+		}
+	}
+	return out
+}
+
+// cropTo keeps the MCUs covering r.
+func (j *jpegCoeffs) cropTo(r image.Rectangle) error {
+	r = r.Intersect(image.Rect(0, 0, j.width, j.height))
+	if r.Empty() {
+		return errors.New("crop is outside the image")
+	}
+	mw, mh := 8*j.hmax, 8*j.vmax
+	r.Min.X -= r.Min.X % mw
+	r.Min.Y -= r.Min.Y % mh
+	x0, y0 := r.Min.X/mw, r.Min.Y/mh
+	j.width, j.height = r.Dx(), r.Dy()
+	mx, my := j.mcus()
+	for _, c := range j.comps {
+		bw, bh := mx*c.h, my*c.v
+		out := make([][64]int32, bw*bh)
+		for y := range bh {
+			for x := range bw {
+				out[y*bw+x] = *c.block(x0*c.h+x, y0*c.v+y)
+			}
+		}
+		c.blocks, c.bw, c.bh = out, bw, bh
+	}
+	return nil
+}
+
+// exifOrientation returns the orientation in the EXIF segment.
+func exifOrientation(segs []jpegSegment) orientation {
+	for _, s := range segs {
+		if !isJPEGEXIF(s) {
+			continue
+		}
+		o := orientNormal
+		walkEXIF(s.data[len(jpegEXIFHeader):], func(tag uint16, bo binary.ByteOrder, value []byte) {
+			if tag == exifOrientationTag {
+				if v := orientation(bo.Uint16(value)); v >= orientNormal && v <= orientRotate270 {
+					o = v
+				}
+			}
+		})
+		return o
+	}
+	return orientNormal
+}
+
+const (
+	exifOrientationTag = 0x0112
+	exifIFDTag         = 0x8769
+	exifPixelXTag      = 0xa002
+	exifPixelYTag      = 0xa003
+)
+
+// walkEXIF calls fn with the value of each SHORT or LONG entry of IFD0 and
+// the EXIF IFD. value can be written to in place.
+func walkEXIF(tiff []byte, fn func(tag uint16, bo binary.ByteOrder, value []byte)) {
+	bo, ifd, err := tiffHeader(tiff)
+	if err != nil {
+		return
+	}
+	var walk func(off int, depth int)
+	walk = func(off int, depth int) {
+		n, err := tiffIFD(tiff, bo, off)
+		if depth > 1 || err != nil {
+			return
+		}
+		for i := range n {
+			e := off + 2 + i*12
+			tag := bo.Uint16(tiff[e:])
+			if tag == exifIFDTag {
+				walk(int(bo.Uint32(tiff[e+8:])), depth+1)
+				continue
+			}
+			typ := bo.Uint16(tiff[e+2:])
+			value := tiffEntryValue(tiff, bo, e)
+			if typ != 3 && typ != 4 || len(value) < tiffTypeSize(typ) {
+				continue
+			}
+			fn(tag, bo, value[:tiffTypeSize(typ)])
+		}
+	}
+	walk(ifd, 0)
+}
+
+var xmpOrientation = regexp.MustCompile(`(tiff:Orientation(?:="|>))\d`)
+
+// updateMeta resets the orientation and sets the dimensions in the EXIF
+// and XMP segments.
+func (j *jpegCoeffs) updateMeta() {
+	for i, s := range j.segs {
+		switch {
+		case isJPEGEXIF(s):
+			d := slices.Clone(s.data)
+			walkEXIF(d[len(jpegEXIFHeader):], func(tag uint16, bo binary.ByteOrder, value []byte) {
+				var v int
+				switch tag {
+				case exifOrientationTag:
+					v = int(orientNormal)
+				case exifPixelXTag:
+					v = j.width
+				case exifPixelYTag:
+					v = j.height
+				default:
+					return
+				}
+				if len(value) == 4 {
+					bo.PutUint32(value, uint32(v))
+				} else {
+					bo.PutUint16(value, uint16(v))
+				}
+			})
+			j.segs[i].data = d
+		case isJPEGXMP(s):
+			j.segs[i].data = xmpOrientation.ReplaceAll(s.data, []byte("${1}1"))
+		}
+	}
+}
+
+// decodeJPEGCoeffs entropy decodes a baseline JPEG.
+func decodeJPEGCoeffs(data []byte) (*jpegCoeffs, error) {
+	segs, scan, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	j := &jpegCoeffs{}
+	var (
+		dc, ac  [4]*huffDecoder
+		restart int
+		scans   int
+	)
+	// parse handles the segment s, which ends at offset i, and returns the
+	// length of any entropy coded data after it.
+	parse := func(s jpegSegment, i int) (int, error) {
+		m := s.marker
+		switch {
+		case m == 0xc0 || m == 0xc1:
+			return 0, j.parseSOF(m, s.data)
+		case m >= 0xc2 && m <= 0xcf && m != 0xc4 && m != 0xc8 && m != 0xcc:
+			return 0, ErrNotLossless
+		case m == 0xdb:
+			return 0, j.parseDQT(s.data)
+		case m == 0xc4:
+			return 0, parseDHT(s.data, &dc, &ac)
+		case m == 0xdd:
+			if len(s.data) < 2 {
+				return 0, &CorruptError{Offset: int64(i), Err: errors.New("bad DRI segment")}
+			}
+			restart = int(binary.BigEndian.Uint16(s.data))
+		case m == 0xda:
+			if j.comps == nil {
+				return 0, &CorruptError{Offset: int64(i), Err: errors.New("SOS before SOF")}
+			}
+			scans++
+			return j.decodeScan(s.data, data[i:], dc, ac, restart)
+		case m >= 0xe0 && m <= 0xef || m == 0xfe:
+			if scans == 0 {
+				j.segs = append(j.segs, s)
+			}
+		case s.standalone():
+		default:
+			return 0, ErrNotLossless
+		}
+		return 0, nil
+	}
+	i := 2
+	for _, s := range segs {
+		i += 4 + len(s.data)
+		_, err := parse(s, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i = len(data) - len(scan); ; {
+		s, next, err := readJPEGSegment(data, i)
+		if err != nil {
+			return nil, err
+		}
+		if s.marker == 0xd9 {
+			break
+		}
+		n, err := parse(s, next)
+		if err != nil {
+			return nil, err
+		}
+		i = next + n
+	}
+	return j, nil
+}
+
+func (j *jpegCoeffs) parseSOF(marker byte, seg []byte) error {
+	if len(seg) < 6 || seg[0] != 8 {
+		return ErrNotLossless
+	}
+	j.sof = marker
+	j.height = int(binary.BigEndian.Uint16(seg[1:]))
+	j.width = int(binary.BigEndian.Uint16(seg[3:]))
+	n := int(seg[5])
+	if j.width == 0 || j.height == 0 || n == 0 || n > 4 || len(seg) < 6+3*n {
+		return &CorruptError{Offset: -1, Err: errors.New("bad SOF segment")}
+	}
+	j.hmax, j.vmax = 1, 1
+	for k := range n {
+		p := seg[6+3*k:]
+		c := &jpegComp{id: p[0], h: int(p[1] >> 4), v: int(p[1] & 15), tq: p[2] & 3}
+		if c.h < 1 || c.h > 4 || c.v < 1 || c.v > 4 {
+			return &CorruptError{Offset: -1, Err: errors.New("bad sampling factor")}
+		}
+		if n == 1 {
+			c.h, c.v = 1, 1
+		}
+		j.hmax, j.vmax = max(j.hmax, c.h), max(j.vmax, c.v)
+		j.comps = append(j.comps, c)
+		j.qtUsed[c.tq] = true
+	}
+	mx, my := j.mcus()
+	for _, c := range j.comps {
+		c.bw, c.bh = mx*c.h, my*c.v
+		c.blocks = make([][64]int32, c.bw*c.bh)
+	}
+	return nil
+}
+
+func (j *jpegCoeffs) parseDQT(seg []byte) error {
+	for len(seg) > 0 {
+		prec, id := seg[0]>>4, seg[0]&3
+		size := 64
+		if prec == 1 {
+			size = 128
+		}
+		if len(seg) < 1+size {
+			return &CorruptError{Offset: -1, Err: errors.New("bad DQT segment")}
+		}
+		for k := range 64 {
+			if prec == 1 {
+				j.qt[id][unzig[k]] = binary.BigEndian.Uint16(seg[1+2*k:])
+			} else {
+				j.qt[id][unzig[k]] = uint16(seg[1+k])
+			}
+		}
+		j.qtPrec[id] = prec
+		seg = seg[1+size:]
+	}
+	return nil
+}
+
+// unzig maps the zig-zag order of coefficients to their natural order.
+var unzig = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+type huffDecoder struct {
+	mincode, maxcode [17]int32
+	valptr           [17]int
+	vals             []byte
+}
+
+func newHuffDecoder(bits [16]byte, vals []byte) *huffDecoder {
+	h := &huffDecoder{vals: vals}
+	code, k := int32(0), 0
+	for l := 1; l <= 16; l++ {
+		n := int(bits[l-1])
+		h.valptr[l] = k
+		h.mincode[l] = code
+		code += int32(n)
+		k += n
+		h.maxcode[l] = -1
+		if n > 0 {
+			h.maxcode[l] = code - 1
+		}
+		code <<= 1
+	}
+	return h
+}
+
+func parseDHT(seg []byte, dc, ac *[4]*huffDecoder) error {
+	for len(seg) > 0 {
+		if len(seg) < 17 {
+			return &CorruptError{Offset: -1, Err: errors.New("bad DHT segment")}
+		}
+		class, id := seg[0]>>4, seg[0]&3
+		var bits [16]byte
+		copy(bits[:], seg[1:17])
+		n := 0
+		for _, b := range bits {
+			n += int(b)
+		}
+		if len(seg) < 17+n || n > 256 {
+			return &CorruptError{Offset: -1, Err: errors.New("bad DHT segment")}
+		}
+		h := newHuffDecoder(bits, slices.Clone(seg[17:17+n]))
+		if class == 0 {
+			dc[id] = h
+		} else {
+			ac[id] = h
+		}
+		seg = seg[17+n:]
+	}
+	return nil
+}
+
+var errScanMarker = errors.New("unexpected marker in jpeg scan")
+
+type bitReader struct {
+	data []byte
+	pos  int
+	acc  uint32
+	n    int
+}
+
+func (r *bitReader) bit() (int32, error) {
+	if r.n == 0 {
+		if r.pos >= len(r.data) {
+			return 0, errScanMarker
+		}
+		b := r.data[r.pos]
+		if b == 0xff {
+			if r.pos+1 >= len(r.data) || r.data[r.pos+1] != 0 {
+				return 0, errScanMarker
+			}
+			r.pos++
+		}
+		r.pos++
+		r.acc, r.n = uint32(b), 8
+	}
+	r.n--
+	return int32(r.acc>>r.n) & 1, nil
+}
+
+func (r *bitReader) bits(s int) (int32, error) {
+	var v int32
+	for range s {
+		b, err := r.bit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | b
+	}
+	return v, nil
+}
+
+func (r *bitReader) receiveExtend(s int) (int32, error) {
+	if s == 0 {
+		return 0, nil
+	}
+	v, err := r.bits(s)
+	if err != nil {
+		return 0, err
+	}
+	if v < 1<<(s-1) {
+		v += -1<<s + 1
+	}
+	return v, nil
+}
+
+func (r *bitReader) decode(h *huffDecoder) (byte, error) {
+	if h == nil {
+		return 0, errors.New("missing huffman table")
+	}
+	code := int32(0)
+	for l := 1; l <= 16; l++ {
+		b, err := r.bit()
+		if err != nil {
+			return 0, err
+		}
+		code = code<<1 | b
+		if code <= h.maxcode[l] {
+			k := h.valptr[l] + int(code-h.mincode[l])
+			if k >= len(h.vals) {
+				break
+			}
+			return h.vals[k], nil
+		}
+	}
+	return 0, errors.New("bad huffman code")
+}
+
+// restart skips to the byte after an RST marker.
+func (r *bitReader) restart() error {
+	r.n = 0
+	if r.pos+1 >= len(r.data) || r.data[r.pos] != 0xff || r.data[r.pos+1] < 0xd0 || r.data[r.pos+1] > 0xd7 {
+		return errors.New("missing jpeg restart marker")
+	}
+	r.pos += 2
+	return nil
+}
+
+// decodeScan decodes the entropy coded data after an SOS header and returns
+// its length.
+func (j *jpegCoeffs) decodeScan(hdr, data []byte, dc, ac [4]*huffDecoder, restart int) (int, error) {
+	if len(hdr) < 1 {
+		return 0, &CorruptError{Offset: -1, Err: errors.New("bad SOS segment")}
+	}
+	n := int(hdr[0])
+	if n < 1 || n > 4 || len(hdr) < 1+2*n+3 {
+		return 0, &CorruptError{Offset: -1, Err: errors.New("bad SOS segment")}
+	}
+	type scanComp struct {
+		c      *jpegComp
+		dc, ac *huffDecoder
+		pred   int32
+	}
+	var sc []*scanComp
+	for k := range n {
+		id, tables := hdr[1+2*k], hdr[2+2*k]
+		idx := slices.IndexFunc(j.comps, func(c *jpegComp) bool { return c.id == id })
+		if idx < 0 {
+			return 0, &CorruptError{Offset: -1, Err: errors.New("unknown scan component")}
+		}
+		sc = append(sc, &scanComp{c: j.comps[idx], dc: dc[tables>>4&3], ac: ac[tables&3]})
+	}
+	r := &bitReader{data: data}
+	readBlock := func(s *scanComp, blk *[64]int32) error {
+		t, err := r.decode(s.dc)
+		if err != nil {
+			return err
+		}
+		diff, err := r.receiveExtend(int(t))
+		if err != nil {
+			return err
+		}
+		s.pred += diff
+		blk[0] = s.pred
+		for k := 1; k < 64; k++ {
+			rs, err := r.decode(s.ac)
+			if err != nil {
+				return err
+			}
+			run, size := int(rs>>4), int(rs&15)
+			if size == 0 {
+				if run != 15 {
+					break
+				}
+				k += 15
+				continue
+			}
+			k += run
+			if k > 63 {
+				return errors.New("bad jpeg coefficient run")
+			}
+			blk[unzig[k]], err = r.receiveExtend(size)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// a single component scan isn't interleaved, so each block is an MCU
+	mx, my := j.mcus()
+	readMCU := func(x, y int) error {
+		for _, s := range sc {
+			for v := range s.c.v {
+				for h := range s.c.h {
+					err := readBlock(s, s.c.block(x*s.c.h+h, y*s.c.v+v))
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	if n == 1 {
+		c := sc[0].c
+		mx = ceilDiv(ceilDiv(j.width*c.h, j.hmax), 8)
+		my = ceilDiv(ceilDiv(j.height*c.v, j.vmax), 8)
+		readMCU = func(x, y int) error {
+			return readBlock(sc[0], c.block(x, y))
+		}
+	}
+	for u := range mx * my {
+		if restart > 0 && u > 0 && u%restart == 0 {
+			err := r.restart()
+			if err != nil {
+				return 0, &CorruptError{Offset: -1, Err: err}
+			}
+			for _, s := range sc {
+				s.pred = 0
+			}
+		}
+		err := readMCU(u%mx, u/mx)
+		if err != nil {
+			return 0, &CorruptError{Offset: -1, Err: err}
+		}
+	}
+	// skip to the next marker
+	pos := r.pos
+	for pos+1 < len(data) && !(data[pos] == 0xff && data[pos+1] != 0 && (data[pos+1] < 0xd0 || data[pos+1] > 0xd7)) {
+		pos++
+	}
+	return pos, nil
+}
+
+// encode writes the coefficients as a baseline JPEG with the standard
+// Huffman tables.
+func (j *jpegCoeffs) encode() []byte {
+	var b bytes.Buffer
+	b.Write([]byte{0xff, 0xd8})
+	writeSeg := func(marker byte, data []byte) {
+		b.Write([]byte{0xff, marker})
+		binary.Write(&b, binary.BigEndian, uint16(len(data)+2))
+		b.Write(data)
+	}
+	for _, s := range j.segs {
+		writeSeg(s.marker, s.data)
+	}
+	for id, used := range j.qtUsed {
+		if !used {
+			continue
+		}
+		d := []byte{j.qtPrec[id]<<4 | byte(id)}
+		for k := range 64 {
+			q := j.qt[id][unzig[k]]
+			if j.qtPrec[id] == 1 {
+				d = binary.BigEndian.AppendUint16(d, q)
+			} else {
+				d = append(d, byte(q))
+			}
+		}
+		writeSeg(0xdb, d)
+	}
+	sof := []byte{8}
+	sof = binary.BigEndian.AppendUint16(sof, uint16(j.height))
+	sof = binary.BigEndian.AppendUint16(sof, uint16(j.width))
+	sof = append(sof, byte(len(j.comps)))
+	for _, c := range j.comps {
+		sof = append(sof, c.id, byte(c.h<<4|c.v), c.tq)
+	}
+	writeSeg(j.sof, sof)
+	var dht []byte
+	for k, spec := range stdHuffman {
+		dht = append(dht, byte(k%2<<4|k/2))
+		dht = append(dht, spec.bits[:]...)
+		dht = append(dht, spec.vals...)
+	}
+	writeSeg(0xc4, dht)
+	sos := []byte{byte(len(j.comps))}
+	for k, c := range j.comps {
+		t := byte(0)
+		if k > 0 {
+			t = 0x11
+		}
+		sos = append(sos, c.id, t)
+	}
+	sos = append(sos, 0, 63, 0)
+	writeSeg(0xda, sos)
+
+	w := &bitWriter{w: &b}
+	var enc [4]huffEncoder
+	for k, spec := range stdHuffman {
+		enc[k] = newHuffEncoder(spec.bits, spec.vals)
+	}
+	preds := make([]int32, len(j.comps))
+	writeBlock := func(k int, blk *[64]int32) {
+		dc, ac := &enc[0], &enc[1]
+		if k > 0 {
+			dc, ac = &enc[2], &enc[3]
+		}
+		diff := blk[0] - preds[k]
+		preds[k] = blk[0]
+		w.emitValue(dc, 0, diff)
+		run := 0
+		for z := 1; z < 64; z++ {
+			c := blk[unzig[z]]
+			if c == 0 {
+				run++
+				continue
+			}
+			for run > 15 {
+				w.emit(ac.codes[0xf0], ac.sizes[0xf0])
+				run -= 16
+			}
+			w.emitValue(ac, run, c)
+			run = 0
+		}
+		if run > 0 {
+			w.emit(ac.codes[0], ac.sizes[0])
+		}
+	}
+	if len(j.comps) == 1 {
+		c := j.comps[0]
+		for y := range ceilDiv(j.height, 8) {
+			for x := range ceilDiv(j.width, 8) {
+				writeBlock(0, c.block(x, y))
+			}
+		}
+	} else {
+		mx, my := j.mcus()
+		for y := range my {
+			for x := range mx {
+				for k, c := range j.comps {
+					for v := range c.v {
+						for h := range c.h {
+							writeBlock(k, c.block(x*c.h+h, y*c.v+v))
+						}
+					}
+				}
+			}
+		}
+	}
+	w.flush()
+	b.Write([]byte{0xff, 0xd9})
+	return b.Bytes()
+}
+
+type huffEncoder struct {
+	codes [256]uint32
+	sizes [256]int
+}
+
+func newHuffEncoder(bits [16]byte, vals []byte) huffEncoder {
+	var h huffEncoder
+	code, k := uint32(0), 0
+	for l := 1; l <= 16; l++ {
+		for range int(bits[l-1]) {
+			h.codes[vals[k]] = code
+			h.sizes[vals[k]] = l
+			code++
+			k++
+		}
+		code <<= 1
+	}
+	return h
+}
+
+type bitWriter struct {
+	w   *bytes.Buffer
+	acc uint32
+	n   int
+}
+
+func (w *bitWriter) emit(code uint32, size int) {
+	for i := size - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | code>>i&1
+		w.n++
+		if w.n == 8 {
+			w.w.WriteByte(byte(w.acc))
+			if byte(w.acc) == 0xff {
+				w.w.WriteByte(0)
+			}
+			w.acc, w.n = 0, 0
+		}
+	}
+}
+
+// emitValue writes the Huffman code for run and the category of v, then
+// the bits of v.
+func (w *bitWriter) emitValue(h *huffEncoder, run int, v int32) {
+	a := v
+	if a < 0 {
+		a = -a
+		v--
+	}
+	size := 0
+	for a > 0 {
+		size++
+		a >>= 1
+	}
+	sym := byte(run<<4 | size)
+	w.emit(h.codes[sym], h.sizes[sym])
+	w.emit(uint32(v)&(1<<size-1), size)
+}
+
+// flush pads the last byte with ones.
+func (w *bitWriter) flush() {
+	if w.n > 0 {
+		w.emit(1<<(8-w.n)-1, 8-w.n)
+	}
+}
+
+// stdHuffman holds the typical tables of section K.3 of the JPEG spec in
+// the order luminance DC, luminance AC, chrominance DC, chrominance AC. They
+// code every symbol a baseline scan can contain.
+var stdHuffman = [4]struct {
+	bits [16]byte
+	vals []byte
+}{
+	{
+		[16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125},
+		[]byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+			0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+			0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+			0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+			0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+			0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+			0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+			0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+			0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+			0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+			0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+			0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+			0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+			0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+	{
+		[16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	{
+		[16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119},
+		[]byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+			0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+			0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+			0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+			0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+			0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+			0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+			0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+			0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+			0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+			0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+			0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+			0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+			0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+			0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+			0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+}
+
+// String returns the name of the transform.
+func (t Transform) String() string {
+	name := map[orientation]string{
+		orientNormal:     "auto-orient",
+		orientFlipH:      "flip-h",
+		orientRotate180:  "rotate-180",
+		orientFlipV:      "flip-v",
+		orientTranspose:  "transpose",
+		orientRotate90:   "rotate-90",
+		orientTransverse: "transverse",
+		orientRotate270:  "rotate-270",
+	}[t.orient]
+	if !t.crop.Empty() {
+		return fmt.Sprintf("crop %v", t.crop)
+	}
+	return name
+}
@@ -0,0 +1,143 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLosslessTransform(t *testing.T) {
+	c := qt.New(t)
+	w, h := 48, 32
+	rgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	gray := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			rgba.Set(x, y, color.NRGBA{uint8(x * 5), uint8(y * 7), uint8((x * y) % 256), 255})
+			gray.Set(x, y, color.Gray{uint8((x*13 + y*29) % 256)})
+		}
+	}
+	// where the pixel at x, y of the original ends up
+	moves := map[Transform]func(x, y int) (int, int){
+		Rotate90:   func(x, y int) (int, int) { return h - 1 - y, x },
+		Rotate180:  func(x, y int) (int, int) { return w - 1 - x, h - 1 - y },
+		Rotate270:  func(x, y int) (int, int) { return y, w - 1 - x },
+		FlipH:      func(x, y int) (int, int) { return w - 1 - x, y },
+		FlipV:      func(x, y int) (int, int) { return x, h - 1 - y },
+		Transpose:  func(x, y int) (int, int) { return y, x },
+		Transverse: func(x, y int) (int, int) { return h - 1 - y, w - 1 - x },
+	}
+	for _, m := range []image.Image{rgba, gray} {
+		var buf bytes.Buffer
+		c.Assert(jpeg.Encode(&buf, m, &jpeg.Options{Quality: 90}), qt.IsNil)
+		orig, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+		c.Assert(err, qt.IsNil)
+
+		for op, move := range moves {
+			out, err := transformJPEG(buf.Bytes(), op)
+			c.Assert(err, qt.IsNil, qt.Commentf("%v", op))
+			got, err := jpeg.Decode(bytes.NewReader(out))
+			c.Assert(err, qt.IsNil, qt.Commentf("%v", op))
+			for y := range h {
+				for x := range w {
+					tx, ty := move(x, y)
+					assertClose(c, got.At(tx, ty), orig.At(x, y), op)
+				}
+			}
+		}
+
+		out, err := transformJPEG(buf.Bytes(), Crop(image.Rect(20, 17, 40, 30)))
+		c.Assert(err, qt.IsNil)
+		got, err := jpeg.Decode(bytes.NewReader(out))
+		c.Assert(err, qt.IsNil)
+		// the corner moves back to the MCU boundary
+		c.Assert(got.Bounds().Min, qt.Equals, image.Point{})
+		c.Assert(got.Bounds().Max.Y, qt.Equals, 14)
+		c.Assert(got.At(0, 0), qt.DeepEquals, orig.At(16, 16))
+	}
+
+	// a partial MCU on a mirrored edge is trimmed
+	var buf bytes.Buffer
+	c.Assert(jpeg.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 40, 20)), nil), qt.IsNil)
+	out, err := transformJPEG(buf.Bytes(), Rotate90)
+	c.Assert(err, qt.IsNil)
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(out))
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int{cfg.Width, cfg.Height}, qt.DeepEquals, []int{16, 40})
+	buf.Reset()
+	c.Assert(jpeg.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 40, 10)), nil), qt.IsNil)
+	_, err = transformJPEG(buf.Bytes(), Rotate180)
+	c.Assert(err, qt.IsNotNil)
+
+	// the EXIF orientation is applied and reset
+	buf.Reset()
+	c.Assert(jpeg.Encode(&buf, rgba, nil), qt.IsNil)
+	exif := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, 6, 0, 0, 0, 0, 0, 0, 0}
+	rotated, err := embedEXIF(JPEG, buf.Bytes(), exif)
+	c.Assert(err, qt.IsNil)
+	out, err = transformJPEG(rotated, AutoOrient)
+	c.Assert(err, qt.IsNil)
+	cfg, err = jpeg.DecodeConfig(bytes.NewReader(out))
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int{cfg.Width, cfg.Height}, qt.DeepEquals, []int{h, w})
+	segs, _, err := splitJPEG(out)
+	c.Assert(err, qt.IsNil)
+	c.Assert(exifOrientation(segs), qt.Equals, orientNormal)
+	again, err := transformJPEG(out, AutoOrient)
+	c.Assert(err, qt.IsNil)
+	c.Assert(again, qt.DeepEquals, out)
+
+	// operations are relative to the displayed image
+	twice, err := transformJPEG(rotated, Rotate90)
+	c.Assert(err, qt.IsNil)
+	cfg, err = jpeg.DecodeConfig(bytes.NewReader(twice))
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int{cfg.Width, cfg.Height}, qt.DeepEquals, []int{w, h})
+
+	// truncated files and EXIF blocks are rejected without reading past
+	// the end
+	for _, n := range []int{3, 8, 40, len(rotated) / 2, len(rotated) - 1} {
+		_, err := transformJPEG(rotated[:n], Rotate90)
+		c.Assert(err, qt.IsNotNil, qt.Commentf("%d bytes", n))
+	}
+	for n := range len(exif) {
+		seg := jpegSegment{marker: 0xe1, data: append([]byte(jpegEXIFHeader), exif[:n]...)}
+		c.Assert(exifOrientation([]jpegSegment{seg}), qt.Equals, orientNormal)
+	}
+
+	// the file is replaced, keeping its mode
+	dir := t.TempDir()
+	name := filepath.Join(dir, "photo.jpg")
+	c.Assert(os.WriteFile(name, rotated, 0600), qt.IsNil)
+	c.Assert(LosslessTransform(name, AutoOrient), qt.IsNil)
+	info, err := os.Stat(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.Mode().Perm(), qt.Equals, os.FileMode(0600))
+	saved, err := os.ReadFile(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(saved, qt.DeepEquals, out)
+	entries, err := os.ReadDir(dir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+}
+
+func assertClose(c *qt.C, got, want color.Color, op Transform) {
+	c.Helper()
+	r1, g1, b1, _ := got.RGBA()
+	r2, g2, b2, _ := want.RGBA()
+	diff := func(a, b uint32) uint32 {
+		if a > b {
+			return (a - b) >> 8
+		}
+		return (b - a) >> 8
+	}
+	if diff(r1, r2) > 2 || diff(g1, g2) > 2 || diff(b1, b2) > 2 {
+		c.Fatalf("%v: got %v, want %v", op, got, want)
+	}
+}
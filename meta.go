@@ -1 +1,495 @@
 package img
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+)
+
+// tagDialects are the fields the tag tree is written to, besides the flat
+// dc:subject.
+var tagDialects = []ExifField{
+	HierarchicalSubject,
+	TagsList,
+	LastKeywordXMP,
+	CatalogSets,
+	Categories,
+}
+
+// Tags returns a copy of the image's tag tree.
+func (img *Img) Tags() *Tags {
+	tags := NewTags()
+	if img.tags == nil {
+		for _, s := range img.xmp.DC.Subject {
+			tags.Add(s)
+		}
+		return tags
+	}
+	for _, p := range img.tags.Paths() {
+		tags.Add(p...)
+	}
+	return tags
+}
+
+// SetTags replaces the image's tags, which are written to dc:subject and
+// every hierarchical keyword field when the image is saved.
+func (img *Img) SetTags(tags *Tags) {
+	img.tags = NewTags()
+	for _, p := range tags.Paths() {
+		img.tags.Add(p...)
+	}
+	img.xmp.DC.Subject = img.tags.StringSlice()
+	img.metaChanged = true
+}
+
+// SetTitle sets dc:title.
+func (img *Img) SetTitle(title ...string) {
+	img.xmp.DC.Title = slices.Clone(title)
+	img.metaChanged = true
+}
+
+// SetCreator sets dc:creator.
+func (img *Img) SetCreator(creator ...string) {
+	img.xmp.DC.Creator = slices.Clone(creator)
+	img.metaChanged = true
+}
+
+// SetDescription sets dc:description.
+func (img *Img) SetDescription(desc ...string) {
+	img.xmp.DC.Description = slices.Clone(desc)
+	img.metaChanged = true
+}
+
+// SetRights sets dc:rights.
+func (img *Img) SetRights(rights ...string) {
+	img.xmp.DC.Rights = slices.Clone(rights)
+	img.metaChanged = true
+}
+
+// ClearMeta removes the title, creator, description, rights and tags.
+func (img *Img) ClearMeta() {
+	img.xmp.DC.Title = nil
+	img.xmp.DC.Creator = nil
+	img.xmp.DC.Description = nil
+	img.xmp.DC.Rights = nil
+	img.xmp.DC.Subject = nil
+	img.tags = NewTags()
+	img.metaChanged = true
+}
+
+// hasDC reports whether any of the Dublin Core or the document ID written by
+// xmpPacket is set.
+func (img *Img) hasDC() bool {
+	return !dcIsEmpty(img.xmp.DC) || img.docID != ""
+}
+
+// xmpPacket returns the image's metadata as an XMP packet.
+func (img *Img) xmpPacket() ([]byte, error) {
+	dc := img.xmp.DC
+	props := []xmpProp{
+		fieldProp(Title, dc.Title...),
+		fieldProp(Byline, dc.Creator...),
+		fieldProp(Description, dc.Description...),
+		fieldProp(Rights, dc.Rights...),
+		fieldProp(Subject, dc.Subject...),
+	}
+	if img.docID != "" {
+		props = append(props, xmpProp{name: xmpDocumentID, values: []string{img.docID}})
+	}
+	tags := img.Tags()
+	if !tags.IsEmpty() {
+		for _, f := range tagDialects {
+			vals, err := tags.MarshalField(f)
+			if err != nil {
+				return nil, err
+			}
+			props = append(props, fieldProp(f, vals...))
+		}
+	}
+	return marshalXMP(props), nil
+}
+
+// embedMeta embeds the image's metadata in encoded data of format f: XMP for
+// every format but CBZ, IPTC for JPEG, text chunks for PNG, the text entries
+// of IFD0 for TIFF and ComicInfo.xml for CBZ.
+func (img *Img) embedMeta(f Format, data []byte) ([]byte, error) {
+	if f == CBZ {
+		data, err := embedComicInfo(data, &img.xmp.DC)
+		if err != nil {
+			return nil, formatErr(f, "embed meta", -1, err)
+		}
+		return data, nil
+	}
+	packet, err := img.xmpPacket()
+	if err != nil {
+		return nil, err
+	}
+	dc := img.xmp.DC
+	if f == TIFF {
+		data, err = embedTIFFValues(data, []tiffValue{
+			{tag: tiffXMPTag, typ: tiffByte, data: packet},
+			tiffASCIIValue(tiffImageDescriptionTag, strings.Join(dc.Description, "\n")),
+			tiffASCIIValue(tiffArtistTag, strings.Join(dc.Creator, "; ")),
+			tiffASCIIValue(tiffCopyrightTag, strings.Join(dc.Rights, "\n")),
+		})
+		if err != nil {
+			return nil, formatErr(f, "embed meta", -1, err)
+		}
+		return data, nil
+	}
+	data, err = embedXMP(f, data, packet)
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case JPEG:
+		iptc, err := marshalIPTC(dc)
+		if err == nil {
+			data, err = embedJPEGIPTC(data, iptc)
+		}
+		if err != nil {
+			return nil, formatErr(f, "embed iptc", -1, err)
+		}
+	case PNG:
+		data, err = embedPNGText(data, map[string]string{
+			"Title":       strings.Join(dc.Title, "\n"),
+			"Author":      strings.Join(dc.Creator, "; "),
+			"Description": strings.Join(dc.Description, "\n"),
+			"Copyright":   strings.Join(dc.Rights, "\n"),
+		})
+		if err != nil {
+			return nil, formatErr(f, "embed text", -1, err)
+		}
+	}
+	return data, nil
+}
+
+// canEmbedXMP reports whether metadata can be written to format f.
+func canEmbedXMP(f Format) bool {
+	switch f {
+	case JPEG, PNG, WEBP, TIFF:
+		return true
+	}
+	return false
+}
+
+// canEmbedMeta reports whether embedMeta can write metadata into the encoded
+// data of format f, which a CBZ holds in its ComicInfo.xml.
+func canEmbedMeta(f Format) bool {
+	return f == CBZ || canEmbedXMP(f)
+}
+
+// source returns the encoded bytes the image was read from. Images made
+// with FromImage have none.
+func (img *Img) source() ([]byte, bool) {
+	if img.data != nil {
+		return img.data, true
+	}
+	if img.file == "" {
+		return nil, false
+	}
+	b, err := fs.ReadFile(img.filesystem(), img.file)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// encodeWithMeta encodes the image as f with its edited metadata embedded.
+func (img *Img) encodeWithMeta(enc *Encoder) ([]byte, error) {
+	i, err := img.image()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = enc.Encode(&buf, i)
+	if err != nil {
+		return nil, err
+	}
+	return img.embedMeta(enc.Format, buf.Bytes())
+}
+
+// SidecarName returns the name of the image's XMP sidecar, photo.xmp for
+// photo.jpg.
+func (img *Img) SidecarName() string {
+	return strings.TrimSuffix(img.file, filepath.Ext(img.file)) + ".xmp"
+}
+
+// WriteSidecar writes the image's metadata to its XMP sidecar, in the file
+// system the image was opened from.
+func (img *Img) WriteSidecar() error {
+	if img.file == "" {
+		return ErrNoFileName
+	}
+	fsys, err := img.writeFS()
+	if err != nil {
+		return err
+	}
+	packet, err := img.xmpPacket()
+	if err != nil {
+		return err
+	}
+	return writeFile(fsys, img.SidecarName(), packet)
+}
+
+// readSidecar reads photo.xmp or photo.jpg.xmp from the image's file system.
+// A missing sidecar isn't an error.
+func (img *Img) readSidecar() (xmp.XMP, *Tags, error) {
+	for _, name := range []string{img.SidecarName(), img.file + ".xmp"} {
+		b, err := fs.ReadFile(img.filesystem(), name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return xmp.XMP{}, nil, err
+		}
+		props, err := parseXMP(bytes.NewReader(b))
+		if err != nil {
+			return xmp.XMP{}, nil, &FormatError{Format: img.Fmt, Op: "decode sidecar", Err: &CorruptError{Offset: -1, Err: err}}
+		}
+		return props.meta()
+	}
+	return xmp.XMP{DC: xmp.DublinCore{}}, NewTags(), nil
+}
+
+// mergeMeta fills the fields missing from a with those of b.
+func mergeMeta(a xmp.XMP, aTags *Tags, b xmp.XMP, bTags *Tags) (xmp.XMP, *Tags) {
+	fill := func(dst *[]string, src []string) {
+		if len(*dst) == 0 {
+			*dst = src
+		}
+	}
+	fill(&a.DC.Title, b.DC.Title)
+	fill(&a.DC.Creator, b.DC.Creator)
+	fill(&a.DC.Description, b.DC.Description)
+	fill(&a.DC.Rights, b.DC.Rights)
+	if a.Exif.DateTimeOriginal.IsZero() {
+		a.Exif.DateTimeOriginal = b.Exif.DateTimeOriginal
+	}
+	if a.Exif.GPSLatitude == 0 && a.Exif.GPSLongitude == 0 {
+		a.Exif.GPSLatitude, a.Exif.GPSLongitude = b.Exif.GPSLatitude, b.Exif.GPSLongitude
+	}
+	if len(a.DC.Subject) == 0 {
+		aTags = bTags
+		a.DC.Subject = b.DC.Subject
+	}
+	return a, aTags
+}
+
+// FieldChange is a field whose values differ between two images. Tags are
+// compared as paths joined with Separator under HierarchicalSubject.
+type FieldChange struct {
+	Field ExifField
+	From  []string
+	To    []string
+}
+
+// Added returns the values in To that aren't in From.
+func (c FieldChange) Added() []string {
+	return missing(c.To, c.From)
+}
+
+// Removed returns the values in From that aren't in To.
+func (c FieldChange) Removed() []string {
+	return missing(c.From, c.To)
+}
+
+func missing(vals, in []string) []string {
+	var out []string
+	for _, v := range vals {
+		if !slices.Contains(in, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// diffFields are the fields compared by DiffMeta, in order.
+var diffFields = []ExifField{Title, Byline, Description, Rights, HierarchicalSubject}
+
+// metaField returns the values of one of the diffFields.
+func (img *Img) metaField(f ExifField) []string {
+	dc := img.xmp.DC
+	switch f {
+	case Title:
+		return dc.Title
+	case Byline:
+		return dc.Creator
+	case Description:
+		return dc.Description
+	case Rights:
+		return dc.Rights
+	case HierarchicalSubject:
+		return img.Tags().Join(Separator)
+	}
+	return nil
+}
+
+// DiffMeta returns the Dublin Core fields and tags that change from a to b.
+// Tags are compared regardless of order.
+func DiffMeta(a, b *Img) []FieldChange {
+	var changes []FieldChange
+	for _, f := range diffFields {
+		from, to := a.metaField(f), b.metaField(f)
+		same := slices.Equal(from, to)
+		if f == HierarchicalSubject {
+			same = len(missing(from, to)) == 0 && len(missing(to, from)) == 0
+		}
+		if !same {
+			changes = append(changes, FieldChange{Field: f, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// MergeStrategy decides how MergeMeta resolves fields set in both images.
+type MergeStrategy int
+
+const (
+	// PreferSrc takes every field set in the source.
+	PreferSrc MergeStrategy = iota
+	// PreferDst only fills in the fields missing from the destination.
+	PreferDst
+	// Union adds the source's creators and tags to the destination's and
+	// otherwise prefers the destination.
+	Union
+)
+
+// MergeMeta merges the Dublin Core and tags of src into dst.
+func MergeMeta(dst, src *Img, strategy MergeStrategy) {
+	pick := func(d, s []string, list bool) []string {
+		switch {
+		case len(s) == 0:
+			return d
+		case len(d) == 0, strategy == PreferSrc:
+			return s
+		case strategy == Union && list:
+			return append(slices.Clone(d), missing(s, d)...)
+		}
+		return d
+	}
+	dc, sdc := dst.xmp.DC, src.xmp.DC
+	dst.SetTitle(pick(dc.Title, sdc.Title, false)...)
+	dst.SetCreator(pick(dc.Creator, sdc.Creator, true)...)
+	dst.SetDescription(pick(dc.Description, sdc.Description, false)...)
+	dst.SetRights(pick(dc.Rights, sdc.Rights, false)...)
+
+	tags, srcTags := dst.Tags(), src.Tags()
+	switch {
+	case srcTags.IsEmpty():
+	case tags.IsEmpty(), strategy == PreferSrc:
+		tags = srcTags
+	case strategy == Union:
+		for _, p := range srcTags.Paths() {
+			tags.Add(p...)
+		}
+	}
+	dst.SetTags(tags)
+}
+
+// CopyMeta copies the metadata of src into each of dsts without re-encoding
+// their pixels. By default the Dublin Core, tags and, where both formats
+// support it, the EXIF block are copied. Passing fields copies only the
+// groups they belong to: title, creator, rights, description or tags. A
+// destination that fails doesn't stop the others; the errors are joined,
+// each naming its file.
+func CopyMeta(src string, dsts []string, fields ...ExifField) error {
+	s, err := New(src)
+	if err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	s.data = raw
+	err = s.ReadMeta()
+	if err != nil {
+		return err
+	}
+	var exif []byte
+	if len(fields) == 0 {
+		exif, err = findEXIF(s.Fmt, raw)
+		if err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for _, name := range dsts {
+		err := copyMetaTo(s, name, exif, fields)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func copyMetaTo(src *Img, name string, exif []byte, fields []ExifField) error {
+	d, err := New(name)
+	if err != nil {
+		return err
+	}
+	if !canEmbedMeta(d.Fmt) {
+		return &FormatError{Format: d.Fmt, Op: "write meta", Err: ErrUnsupportedFormat}
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	d.data = b
+	err = d.ReadMeta()
+	if err != nil {
+		return err
+	}
+	d.copyFields(src, fields)
+	b, err = d.embedMeta(d.Fmt, b)
+	if err != nil {
+		return err
+	}
+	if exif != nil && canEmbedEXIF(d.Fmt) {
+		b, err = embedEXIF(d.Fmt, b, exif)
+		if err != nil {
+			return err
+		}
+	}
+	return writeFile(osFS, name, b)
+}
+
+// copyFields replaces the field groups of img with those of src, or all of
+// them if fields is empty.
+func (img *Img) copyFields(src *Img, fields []ExifField) {
+	has := func(group ...ExifField) bool {
+		if len(fields) == 0 {
+			return true
+		}
+		for _, f := range fields {
+			if slices.Contains(group, f) {
+				return true
+			}
+		}
+		return false
+	}
+	dc := src.xmp.DC
+	if has(titleFields...) {
+		img.SetTitle(dc.Title...)
+	}
+	if has(Source, Byline, Credit) {
+		img.SetCreator(dc.Creator...)
+	}
+	if has(Rights, Copyright) {
+		img.SetRights(dc.Rights...)
+	}
+	if has(captionFields...) {
+		img.SetDescription(dc.Description...)
+	}
+	if has(append(slices.Clone(hTagFields), flatTagFields...)...) {
+		img.SetTags(src.Tags())
+	}
+}
@@ -0,0 +1,225 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDiffMergeMeta(t *testing.T) {
+	c := qt.New(t)
+	newImg := func(title string, creator []string, tags ...string) *Img {
+		i := FromImage(image.NewNRGBA(image.Rect(0, 0, 1, 1)), PNG)
+		if title != "" {
+			i.SetTitle(title)
+		}
+		i.SetCreator(creator...)
+		i.SetTags(FromPaths(tags, ""))
+		return i
+	}
+	a := newImg("A", []string{"Ann"}, "Places > NYC", "People")
+	b := newImg("B", []string{"Ann"}, "People", "Places > NYC", "Places > Rome")
+
+	changes := DiffMeta(a, b)
+	c.Assert(changes, qt.HasLen, 2)
+	c.Assert(changes[0], qt.DeepEquals, FieldChange{Field: Title, From: []string{"A"}, To: []string{"B"}})
+	c.Assert(changes[1].Field, qt.Equals, HierarchicalSubject)
+	c.Assert(changes[1].Added(), qt.DeepEquals, []string{"Places > Rome"})
+	c.Assert(changes[1].Removed(), qt.HasLen, 0)
+	c.Assert(DiffMeta(a, a), qt.HasLen, 0)
+
+	dst := newImg("", []string{"Bob"}, "People")
+	MergeMeta(dst, b, PreferDst)
+	c.Assert(dst.DublinCore().Title, qt.DeepEquals, []string{"B"})
+	c.Assert(dst.DublinCore().Creator, qt.DeepEquals, []string{"Bob"})
+	c.Assert(dst.Tags().Paths(), qt.DeepEquals, [][]string{{"People"}})
+
+	MergeMeta(dst, a, Union)
+	c.Assert(dst.DublinCore().Title, qt.DeepEquals, []string{"B"})
+	c.Assert(dst.DublinCore().Creator, qt.DeepEquals, []string{"Bob", "Ann"})
+	c.Assert(dst.Tags().Paths(), qt.DeepEquals, [][]string{{"People"}, {"Places", "NYC"}})
+
+	MergeMeta(dst, a, PreferSrc)
+	c.Assert(DiffMeta(dst, a), qt.HasLen, 0)
+}
+
+func TestReadMetaWithoutMeta(t *testing.T) {
+	c := qt.New(t)
+	name := filepath.Join(t.TempDir(), "anim.gif")
+	c.Assert(Save(name, image.NewNRGBA(image.Rect(0, 0, 2, 2))), qt.IsNil)
+	i, err := New(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.ReadMeta(), qt.IsNil)
+	c.Assert(i.DublinCore().Identifier, qt.Equals, name)
+	c.Assert(i.DublinCore().Title, qt.HasLen, 0)
+}
+
+func TestCopyMeta(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	m := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+
+	// a little-endian TIFF structure with an ImageDescription
+	desc := "From EXIF\x00"
+	exif := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x0e, 0x01, 2, 0, byte(len(desc)), 0, 0, 0, 26, 0, 0, 0, 0, 0, 0, 0}
+	exif = append(exif, desc...)
+
+	var buf bytes.Buffer
+	c.Assert(NewEncoder(JPEG).Encode(&buf, m), qt.IsNil)
+	src, err := embedEXIF(JPEG, buf.Bytes(), exif)
+	c.Assert(err, qt.IsNil)
+	srcName := filepath.Join(dir, "src.jpg")
+	c.Assert(os.WriteFile(srcName, src, 0644), qt.IsNil)
+
+	var dsts []string
+	for _, f := range []Format{PNG, WEBP, JPEG} {
+		name := filepath.Join(dir, "dst"+f.String())
+		i := FromImage(m, f)
+		i.SetTitle("Old")
+		c.Assert(i.SaveAs(name), qt.IsNil)
+		dsts = append(dsts, name)
+	}
+	c.Assert(CopyMeta(srcName, dsts[:1], Description), qt.IsNil)
+	i, err := New(dsts[0])
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.ReadMeta(), qt.IsNil)
+	c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Old"})
+	c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"From EXIF"})
+
+	c.Assert(CopyMeta(srcName, dsts), qt.IsNil)
+	for _, name := range dsts {
+		i, err := New(name)
+		c.Assert(err, qt.IsNil)
+		b, err := os.ReadFile(name)
+		c.Assert(err, qt.IsNil)
+		got, err := findEXIF(i.Fmt, b)
+		c.Assert(err, qt.IsNil)
+		c.Assert(got, qt.DeepEquals, exif)
+		c.Assert(i.ReadMeta(), qt.IsNil)
+		c.Assert(i.DublinCore().Title, qt.HasLen, 0)
+		_, err = i.Fmt.Decode(bytes.NewReader(b))
+		c.Assert(err, qt.IsNil)
+	}
+
+	// the metadata entries of a TIFF are copied as EXIF, and a destination
+	// that can't take metadata doesn't stop the others
+	buf.Reset()
+	c.Assert(NewEncoder(TIFF).Encode(&buf, m), qt.IsNil)
+	tif, err := embedTIFFValues(buf.Bytes(), []tiffValue{tiffASCIIValue(tiffImageDescriptionTag, "From TIFF")})
+	c.Assert(err, qt.IsNil)
+	tifName := filepath.Join(dir, "src.tif")
+	c.Assert(os.WriteFile(tifName, tif, 0644), qt.IsNil)
+	gifName := filepath.Join(dir, "dst.gif")
+	c.Assert(FromImage(m, GIF).SaveAs(gifName), qt.IsNil)
+	err = CopyMeta(tifName, []string{dsts[0], gifName, dsts[2]})
+	c.Assert(err, qt.ErrorIs, ErrUnsupportedFormat)
+	c.Assert(err, qt.ErrorMatches, `(?s).*dst\.gif: gif write meta: .*`)
+	for _, name := range []string{dsts[0], dsts[2]} {
+		i, err := New(name)
+		c.Assert(err, qt.IsNil)
+		b, err := os.ReadFile(name)
+		c.Assert(err, qt.IsNil)
+		got, err := findEXIF(i.Fmt, b)
+		c.Assert(err, qt.IsNil)
+		// just IFD0 with the description, without the strips of the image
+		c.Assert(got, qt.HasLen, 8+2+12+4+len("From TIFF\x00"))
+		c.Assert(bytes.Contains(got, []byte("From TIFF")), qt.IsTrue)
+		c.Assert(i.ReadMeta(), qt.IsNil)
+		c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"From TIFF"})
+	}
+
+	// a CBZ takes the metadata in its ComicInfo.xml
+	cbzName := filepath.Join(dir, "dst.cbz")
+	c.Assert(SaveAll(cbzName, []image.Image{m, m}, CBZPageFormat(PNG)), qt.IsNil)
+	c.Assert(CopyMeta(srcName, []string{cbzName}), qt.IsNil)
+	i, err = Open(cbzName, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"From EXIF"})
+	b, err := os.ReadFile(cbzName)
+	c.Assert(err, qt.IsNil)
+	pages, err := countCBZPages(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pages, qt.Equals, 2)
+}
+
+func TestLosslessSave(t *testing.T) {
+	c := qt.New(t)
+	fsys := DirFS(t.TempDir())
+	m := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	m.Set(2, 2, image.White)
+	for _, f := range []Format{JPEG, PNG, WEBP, TIFF} {
+		var buf bytes.Buffer
+		c.Assert(NewEncoder(f).Encode(&buf, m), qt.IsNil)
+		orig := buf.Bytes()
+
+		i, err := FromBytes(orig)
+		c.Assert(err, qt.IsNil)
+		i.SetTitle("Café")
+		i.SetCreator("Ann")
+		i.SetDescription("Described")
+		name := "out" + f.String()
+		c.Assert(i.SaveAsFS(fsys, name), qt.IsNil)
+		saved, err := fs.ReadFile(fsys, name)
+		c.Assert(err, qt.IsNil)
+
+		switch f {
+		case JPEG:
+			_, scan, err := splitJPEG(orig)
+			c.Assert(err, qt.IsNil)
+			_, savedScan, err := splitJPEG(saved)
+			c.Assert(err, qt.IsNil)
+			c.Assert(savedScan, qt.DeepEquals, scan)
+		case TIFF:
+			// only the IFD at the end of the file is rewritten
+			ifd := int(binary.LittleEndian.Uint32(orig[4:]))
+			c.Assert(saved[8:ifd], qt.DeepEquals, orig[8:ifd])
+		case PNG:
+			chunks, err := splitPNG(saved)
+			c.Assert(err, qt.IsNil)
+			text := map[string]string{}
+			for _, ch := range chunks {
+				if ch.typ == "tEXt" {
+					kw, v, _ := bytes.Cut(ch.data, []byte{0})
+					text[string(kw)] = string(v)
+				}
+			}
+			c.Assert(text["Author"], qt.Equals, "Ann")
+			c.Assert(text["Title"], qt.Equals, "Caf\xe9")
+		}
+
+		j, err := FromBytes(saved)
+		c.Assert(err, qt.IsNil)
+		c.Assert(j.DublinCore().Title, qt.DeepEquals, []string{"Café"})
+		c.Assert(j.img.Bounds(), qt.Equals, m.Bounds())
+	}
+
+	// the IFD0 text entries are read back as EXIF
+	var buf bytes.Buffer
+	c.Assert(NewEncoder(TIFF).Encode(&buf, m), qt.IsNil)
+	b, err := embedTIFFValues(buf.Bytes(), []tiffValue{tiffASCIIValue(tiffImageDescriptionTag, "In IFD0")})
+	c.Assert(err, qt.IsNil)
+	i, err := FromBytes(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"In IFD0"})
+
+	// saving again reuses the space of the last save
+	sizes := map[int]bool{}
+	for _, title := range []string{"One", "Two", "Six", "Ten"} {
+		i.SetTitle(title)
+		c.Assert(i.SaveAsFS(fsys, "again.tif"), qt.IsNil)
+		b, err := fs.ReadFile(fsys, "again.tif")
+		c.Assert(err, qt.IsNil)
+		sizes[len(b)] = true
+		i, err = FromBytes(b)
+		c.Assert(err, qt.IsNil)
+		c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{title})
+		c.Assert(i.DublinCore().Description, qt.DeepEquals, []string{"In IFD0"})
+	}
+	c.Assert(sizes, qt.HasLen, 1)
+}
@@ -0,0 +1,114 @@
+package img
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// pdfContext reads and validates a PDF. Without validation the pages of a
+// PDF written by pdfcpu, as Encode does, aren't counted.
+func pdfContext(r io.Reader) (*model.Context, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		rs = bytes.NewReader(b)
+	}
+	ctx, err := api.ReadValidateAndOptimize(rs, model.NewDefaultConfiguration())
+	if err != nil {
+		return nil, err
+	}
+	if ctx.PageCount == 0 {
+		return nil, ErrCorrupt
+	}
+	return ctx, nil
+}
+
+// pdfPageImages returns the images embedded in a page in object order.
+func pdfPageImages(ctx *model.Context, page int) ([]model.Image, error) {
+	m, err := pdfcpu.ExtractPageImages(ctx, page, false)
+	if err != nil {
+		return nil, err
+	}
+	var imgs []model.Image
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		imgs = append(imgs, m[k])
+	}
+	return imgs, nil
+}
+
+// decodePDF decodes the images embedded in the pages of a PDF, stopping
+// after the first unless all is set. The pixel limits in opts are checked for
+// each image.
+func decodePDF(r io.Reader, all bool, opts ...DecodeOption) ([]image.Image, error) {
+	dec := newDecoder(PDF, opts...)
+	ctx, err := pdfContext(r)
+	if err != nil {
+		return nil, err
+	}
+	var imgs []image.Image
+	for page := 1; page <= ctx.PageCount; page++ {
+		embedded, err := pdfPageImages(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range embedded {
+			var r io.Reader = e
+			if dec.hasPixelLimits() {
+				b, err := io.ReadAll(e)
+				if err != nil {
+					return nil, err
+				}
+				cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+				if err != nil {
+					return nil, err
+				}
+				err = dec.checkConfig(cfg)
+				if err != nil {
+					return nil, err
+				}
+				r = bytes.NewReader(b)
+			}
+			img, _, err := image.Decode(r)
+			if err != nil {
+				return nil, err
+			}
+			imgs = append(imgs, img)
+			if !all {
+				return imgs, nil
+			}
+		}
+	}
+	if len(imgs) == 0 {
+		return nil, ErrCorrupt
+	}
+	return imgs, nil
+}
+
+// decodePDFConfig returns the config of the first image embedded in a PDF.
+func decodePDFConfig(r io.Reader) (image.Config, error) {
+	ctx, err := pdfContext(r)
+	if err != nil {
+		return image.Config{}, err
+	}
+	for page := 1; page <= ctx.PageCount; page++ {
+		embedded, err := pdfPageImages(ctx, page)
+		if err != nil {
+			return image.Config{}, err
+		}
+		if len(embedded) > 0 {
+			cfg, _, err := image.DecodeConfig(embedded[0])
+			return cfg, err
+		}
+	}
+	return image.Config{}, ErrCorrupt
+}
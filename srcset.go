@@ -0,0 +1,227 @@
+package img
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Srcset lists the variants of an image written by GenerateSrcset.
+type Srcset struct {
+	Images []SrcsetImage `json:"images" yaml:"images"`
+	// Alt is the image's title, used as the alt text of the HTML.
+	Alt string `json:"alt,omitempty" yaml:"alt,omitempty"`
+}
+
+// SrcsetImage is a single width and format of an image.
+type SrcsetImage struct {
+	// Path is where the variant was written, or its data URL when the
+	// encode options include Base64.
+	Path   string `json:"path" yaml:"path"`
+	Width  int    `json:"width" yaml:"width"`
+	Height int    `json:"height" yaml:"height"`
+	Bytes  int64  `json:"bytes" yaml:"bytes"`
+	MIME   string `json:"mime" yaml:"mime"`
+}
+
+// GenerateSrcset resizes img to each of widths and encodes every width in
+// each of formats, next to the image's file as name-640w.webp and so on.
+// Widths larger than the image are skipped rather than upscaled. With a
+// Base64 option nothing is written and each Path is a data URL.
+func GenerateSrcset(img *Img, widths []int, formats []Format, opts ...EncodeOption) (*Srcset, error) {
+	if img.file == "" {
+		return nil, ErrNoFileName
+	}
+	name := strings.TrimSuffix(img.file, filepath.Ext(img.file))
+	return GenerateSrcsetFS(osFS, name, img, widths, formats, opts...)
+}
+
+// GenerateSrcsetFS is like GenerateSrcset but writes the variants to fsys,
+// naming them after name.
+func GenerateSrcsetFS(fsys WriteFS, name string, img *Img, widths []int, formats []Format, opts ...EncodeOption) (*Srcset, error) {
+	src, err := img.image()
+	if err != nil {
+		return nil, err
+	}
+	// the encoders drop the EXIF that would turn the variants upright
+	src = orientImage(src, img.orientation())
+	b := src.Bounds()
+	set := &Srcset{}
+	if len(img.xmp.DC.Title) > 0 {
+		set.Alt = img.xmp.DC.Title[0]
+	}
+	for _, f := range formats {
+		for _, w := range srcsetWidths(widths, b.Dx()) {
+			h := max((b.Dy()*w+b.Dx()/2)/b.Dx(), 1)
+			i := src
+			if w != b.Dx() {
+				dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+				xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, b, xdraw.Src, nil)
+				i = dst
+			}
+			v, err := encodeVariant(fsys, fmt.Sprintf("%s-%dw%s", name, w, f), f, i, opts)
+			if err != nil {
+				return nil, err
+			}
+			v.Width, v.Height = w, h
+			set.Images = append(set.Images, v)
+		}
+	}
+	return set, nil
+}
+
+// orientation returns the EXIF orientation of the image's encoded data.
+func (img *Img) orientation() orientation {
+	b := img.data
+	if b == nil && img.file != "" {
+		b, _ = fs.ReadFile(img.filesystem(), img.file)
+	}
+	exif, err := findEXIF(img.Fmt, b)
+	if err != nil || exif == nil {
+		return orientNormal
+	}
+	return tiffOrientation(exif)
+}
+
+// orientImage returns the displayed image of pixels stored with
+// orientation o.
+func orientImage(src image.Image, o orientation) image.Image {
+	m, ok := orientMatrix[o]
+	if !ok || o == orientNormal {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if o.transposed() {
+		w, h = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			sx, sy := x-b.Min.X, y-b.Min.Y
+			dx, dy := m[0]*sx+m[1]*sy, m[2]*sx+m[3]*sy
+			if m[0]+m[1] < 0 {
+				dx += w - 1
+			}
+			if m[2]+m[3] < 0 {
+				dy += h - 1
+			}
+			dst.Set(dx, dy, src.At(x, y))
+		}
+	}
+	return dst
+}
+
+// srcsetWidths sorts and dedupes widths, dropping those wider than limit. The
+// image's own width is used if none are left.
+func srcsetWidths(widths []int, limit int) []int {
+	var out []int
+	for _, w := range widths {
+		if w > 0 && w <= limit {
+			out = append(out, w)
+		}
+	}
+	if len(out) == 0 {
+		return []int{limit}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+func encodeVariant(fsys WriteFS, name string, f Format, i image.Image, opts []EncodeOption) (SrcsetImage, error) {
+	v := SrcsetImage{Path: name, MIME: f.MimeType()}
+	enc := NewEncoder(f, opts...)
+	if enc.toBase64 {
+		enc.toBase64 = false
+		var buf bytes.Buffer
+		err := enc.Encode(&buf, i)
+		if err != nil {
+			return v, err
+		}
+		v.Path = dataURL(f, base64.StdEncoding.EncodeToString(buf.Bytes()), false)
+		v.Bytes = int64(buf.Len())
+		return v, nil
+	}
+	w, err := fsys.Create(name)
+	if err != nil {
+		return v, err
+	}
+	cw := &countingWriter{w: w}
+	err = enc.Encode(cw, i)
+	v.Bytes = cw.n
+	if err != nil {
+		w.Close()
+		return v, err
+	}
+	return v, w.Close()
+}
+
+// Srcset returns the srcset attribute for the variants of type mime.
+func (s *Srcset) Srcset(mime string) string {
+	var c []string
+	for _, v := range s.Images {
+		if v.MIME == mime {
+			c = append(c, fmt.Sprintf("%s %dw", srcsetURL(v.Path), v.Width))
+		}
+	}
+	return strings.Join(c, ", ")
+}
+
+// HTML returns a <picture> with a <source> for each format but the last,
+// which is the fallback <img>. With a single format it is just the <img>.
+func (s *Srcset) HTML() string {
+	if len(s.Images) == 0 {
+		return ""
+	}
+	var mimes []string
+	for _, v := range s.Images {
+		if !slices.Contains(mimes, v.MIME) {
+			mimes = append(mimes, v.MIME)
+		}
+	}
+	fallback := mimes[len(mimes)-1]
+	var largest SrcsetImage
+	for _, v := range s.Images {
+		if v.MIME == fallback {
+			largest = v
+		}
+	}
+
+	var b strings.Builder
+	if len(mimes) > 1 {
+		b.WriteString("<picture>\n")
+		for _, m := range mimes[:len(mimes)-1] {
+			fmt.Fprintf(&b, "  <source type=\"%s\" srcset=\"%s\">\n", m, html.EscapeString(s.Srcset(m)))
+		}
+		b.WriteString("  ")
+	}
+	fmt.Fprintf(&b, `<img src="%s" srcset="%s" width="%d" height="%d" alt="%s">`,
+		html.EscapeString(srcsetURL(largest.Path)),
+		html.EscapeString(s.Srcset(fallback)),
+		largest.Width,
+		largest.Height,
+		html.EscapeString(s.Alt),
+	)
+	if len(mimes) > 1 {
+		b.WriteString("\n</picture>")
+	}
+	return b.String()
+}
+
+// srcsetURL makes a path usable in a URL. Commas and spaces would split a
+// srcset candidate.
+func srcsetURL(p string) string {
+	if strings.HasPrefix(p, "data:") {
+		return p
+	}
+	p = filepath.ToSlash(p)
+	return strings.NewReplacer(" ", "%20", ",", "%2C").Replace(p)
+}
@@ -0,0 +1,142 @@
+package img
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestGenerateSrcset(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "photo.jpg")
+	var buf bytes.Buffer
+	c.Assert(jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 800, 600)), nil), qt.IsNil)
+	c.Assert(os.WriteFile(name, buf.Bytes(), 0644), qt.IsNil)
+	i, err := New(name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.ReadMeta(), qt.IsNil)
+	i.SetTitle(`A "photo"`)
+
+	set, err := GenerateSrcset(i, []int{640, 320, 1600, 320}, []Format{WEBP, JPEG})
+	c.Assert(err, qt.IsNil)
+	// 1600 isn't upscaled and the duplicate 320 is dropped
+	c.Assert(set.Images, qt.HasLen, 4)
+	for k, want := range []struct {
+		name string
+		w, h int
+		mime string
+	}{
+		{"photo-320w.webp", 320, 240, "image/webp"},
+		{"photo-640w.webp", 640, 480, "image/webp"},
+		{"photo-320w.jpg", 320, 240, "image/jpeg"},
+		{"photo-640w.jpg", 640, 480, "image/jpeg"},
+	} {
+		v := set.Images[k]
+		c.Assert(v.Path, qt.Equals, filepath.Join(dir, want.name))
+		c.Assert(v.Width, qt.Equals, want.w)
+		c.Assert(v.Height, qt.Equals, want.h)
+		c.Assert(v.MIME, qt.Equals, want.mime)
+		info, err := os.Stat(v.Path)
+		c.Assert(err, qt.IsNil)
+		c.Assert(v.Bytes, qt.Equals, info.Size())
+		f, err := os.Open(v.Path)
+		c.Assert(err, qt.IsNil)
+		vf, _ := FormatFromFilename(v.Path)
+		cfg, err := vf.DecodeConfig(f)
+		f.Close()
+		c.Assert(err, qt.IsNil)
+		c.Assert([]int{cfg.Width, cfg.Height}, qt.DeepEquals, []int{want.w, want.h})
+	}
+
+	html := set.HTML()
+	c.Assert(strings.HasPrefix(html, "<picture>"), qt.IsTrue)
+	c.Assert(html, qt.Contains, `<source type="image/webp" srcset="`+filepath.ToSlash(filepath.Join(dir, "photo-320w.webp"))+` 320w, `)
+	c.Assert(html, qt.Contains, `src="`+filepath.ToSlash(filepath.Join(dir, "photo-640w.jpg"))+`"`)
+	c.Assert(html, qt.Contains, `width="640" height="480" alt="A &#34;photo&#34;">`)
+
+	// every width is too large, so the image's own is used
+	set, err = GenerateSrcsetFS(DirFS(dir), "small", i, []int{2000}, []Format{PNG}, Base64(URL))
+	c.Assert(err, qt.IsNil)
+	c.Assert(set.Images, qt.HasLen, 1)
+	c.Assert(set.Images[0].Width, qt.Equals, 800)
+	c.Assert(strings.HasPrefix(set.Images[0].Path, "data:image/png;base64,"), qt.IsTrue)
+	_, err = os.Stat(filepath.Join(dir, "small-800w.png"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+	c.Assert(strings.HasPrefix(set.HTML(), "<img src=\"data:image/png;base64,"), qt.IsTrue)
+
+	_, err = GenerateSrcset(FromImage(image.NewGray(image.Rect(0, 0, 4, 4)), PNG), []int{2}, []Format{PNG})
+	c.Assert(err, qt.ErrorIs, ErrNoFileName)
+
+	// an error closing a variant is returned
+	_, err = GenerateSrcsetFS(closeErrFS{DirFS(dir)}, "closed", i, []int{100}, []Format{PNG})
+	c.Assert(err, qt.ErrorIs, errClose)
+
+	// a photo stored sideways is turned upright by its EXIF orientation
+	stored := image.NewNRGBA(image.Rect(0, 0, 80, 40))
+	for y := range 40 {
+		for x := range 80 {
+			if x < 40 {
+				stored.Set(x, y, color.NRGBA{255, 0, 0, 255})
+			} else {
+				stored.Set(x, y, color.NRGBA{0, 0, 255, 255})
+			}
+		}
+	}
+	buf.Reset()
+	c.Assert(jpeg.Encode(&buf, stored, nil), qt.IsNil)
+	rotate90 := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0, 0x12, 0x01, 3, 0, 1, 0, 0, 0, 6, 0, 0, 0, 0, 0, 0, 0}
+	b, err := embedEXIF(JPEG, buf.Bytes(), rotate90)
+	c.Assert(err, qt.IsNil)
+	name = filepath.Join(dir, "portrait.jpg")
+	c.Assert(os.WriteFile(name, b, 0644), qt.IsNil)
+	i, err = New(name)
+	c.Assert(err, qt.IsNil)
+	set, err = GenerateSrcset(i, []int{20}, []Format{PNG})
+	c.Assert(err, qt.IsNil)
+	c.Assert([]int{set.Images[0].Width, set.Images[0].Height}, qt.DeepEquals, []int{20, 40})
+	f, err := os.Open(set.Images[0].Path)
+	c.Assert(err, qt.IsNil)
+	defer f.Close()
+	v, err := PNG.Decode(f)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v.Bounds().Size(), qt.Equals, image.Pt(20, 40))
+	// the left of the stored pixels is the top of the photo
+	r, _, bl, _ := v.At(10, 5).RGBA()
+	c.Assert(r > bl, qt.IsTrue)
+	r, _, bl, _ = v.At(10, 35).RGBA()
+	c.Assert(r < bl, qt.IsTrue)
+}
+
+var errClose = errors.New("close failed")
+
+// closeErrFS is a WriteFS whose files fail to close.
+type closeErrFS struct {
+	WriteFS
+}
+
+func (fsys closeErrFS) Create(name string) (io.WriteCloser, error) {
+	w, err := fsys.WriteFS.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return closeErrWriter{w}, nil
+}
+
+type closeErrWriter struct {
+	io.WriteCloser
+}
+
+func (w closeErrWriter) Close() error {
+	w.WriteCloser.Close()
+	return errClose
+}
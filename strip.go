@@ -0,0 +1,440 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+)
+
+// StripPolicy says which metadata StripMeta removes.
+type StripPolicy struct {
+	gpsOnly bool
+	keep    []ExifField
+}
+
+var (
+	// StripAll removes all metadata but the EXIF orientation and ICC
+	// profile, which are needed to display the image correctly.
+	StripAll = StripPolicy{}
+	// StripGPS removes only the location from EXIF and XMP.
+	StripGPS = StripPolicy{gpsOnly: true}
+	// KeepCopyright removes all metadata but the creator and rights.
+	KeepCopyright = KeepFields(Byline, Rights)
+)
+
+// KeepFields returns a StripPolicy that removes all metadata but the groups
+// the fields belong to: title, creator, rights, description or tags.
+func KeepFields(fields ...ExifField) StripPolicy {
+	return StripPolicy{keep: slices.Clone(fields)}
+}
+
+// keptMeta returns img with only the Dublin Core and tags that p keeps. The
+// copy is marked as changed if anything is left to embed.
+func (img *Img) keptMeta(p StripPolicy) *Img {
+	if p.gpsOnly {
+		return img
+	}
+	k := *img
+	k.xmp = xmp.XMP{DC: xmp.DublinCore{Identifier: img.xmp.DC.Identifier, Format: img.xmp.DC.Format}}
+	k.tags = NewTags()
+	k.docID = ""
+	if len(p.keep) > 0 {
+		k.copyFields(img, p.keep)
+	}
+	k.metaChanged = k.hasDC()
+	return &k
+}
+
+// canStripMeta reports whether stripMeta supports format f.
+func canStripMeta(f Format) bool {
+	return canEmbedMeta(f)
+}
+
+// stripMeta removes metadata from encoded data of format f without
+// re-encoding the pixels. Unless p only removes GPS, everything but the
+// orientation and ICC profile is dropped; the fields p keeps are embedded
+// again afterwards.
+func stripMeta(f Format, data []byte, p StripPolicy) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch f {
+	case JPEG:
+		b, err = stripJPEG(data, p.gpsOnly)
+	case PNG:
+		b, err = stripPNG(data, p.gpsOnly)
+	case WEBP:
+		b, err = stripWEBP(data, p.gpsOnly)
+	case TIFF:
+		b, err = stripTIFF(data, p.gpsOnly)
+	case CBZ:
+		// a CBZ has no GPS
+		b = data
+		if !p.gpsOnly {
+			b, err = embedComicInfo(data, nil)
+		}
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, formatErr(f, "strip meta", -1, err)
+	}
+	return b, nil
+}
+
+// keepJPEGSegment reports whether a segment describes the image rather than
+// being metadata: the tables and frame header, JFIF, ICC profile and Adobe
+// color transform.
+func keepJPEGSegment(s jpegSegment) bool {
+	switch {
+	case s.marker == 0xe0:
+		return bytes.HasPrefix(s.data, []byte("JFIF\x00"))
+	case s.marker == 0xe2:
+		return bytes.HasPrefix(s.data, []byte("ICC_PROFILE\x00"))
+	case s.marker == 0xee:
+		return bytes.HasPrefix(s.data, []byte("Adobe"))
+	case s.marker >= 0xe0 && s.marker <= 0xef, s.marker == 0xfe:
+		return false
+	}
+	return true
+}
+
+func stripJPEG(data []byte, gpsOnly bool) ([]byte, error) {
+	segs, scan, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		out    []jpegSegment
+		orient orientation
+	)
+	for _, s := range segs {
+		switch {
+		case isJPEGEXIF(s):
+			if !gpsOnly {
+				orient = exifOrientation([]jpegSegment{s})
+				continue
+			}
+			d := slices.Clone(s.data)
+			err := stripEXIFGPS(d[len(jpegEXIFHeader):])
+			if err != nil {
+				return nil, err
+			}
+			s.data = d
+		case gpsOnly && isJPEGXMP(s):
+			s.data = blankXMPGPS(s.data)
+		case !gpsOnly && !keepJPEGSegment(s):
+			continue
+		}
+		out = append(out, s)
+	}
+	b := joinJPEG(out, scan)
+	if orient > orientNormal {
+		return embedJPEGEXIF(b, orientationEXIF(orient))
+	}
+	return b, nil
+}
+
+// pngMetaChunks are the ancillary chunks removed by StripAll.
+var pngMetaChunks = []string{"tEXt", "zTXt", "iTXt", "eXIf", "tIME"}
+
+func stripPNG(data []byte, gpsOnly bool) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		out    []pngChunk
+		orient orientation
+		packet []byte
+	)
+	for _, c := range chunks {
+		switch {
+		case c.typ == "eXIf" && gpsOnly:
+			d := slices.Clone(c.data)
+			err := stripEXIFGPS(d)
+			if err != nil {
+				return nil, err
+			}
+			c.data = d
+		case c.typ == "eXIf":
+			orient = tiffOrientation(c.data)
+			continue
+		case gpsOnly && isPNGXMP(c):
+			p, err := pngITXtText(c.data)
+			if err != nil {
+				return nil, err
+			}
+			if blanked := blankXMPGPS(p); !bytes.Equal(blanked, p) {
+				packet = blanked
+			}
+		case !gpsOnly && slices.Contains(pngMetaChunks, c.typ):
+			continue
+		}
+		out = append(out, c)
+	}
+	b := joinPNG(out)
+	if packet != nil {
+		b, err = embedPNGXMP(b, packet)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if orient > orientNormal {
+		return embedPNGEXIF(b, orientationEXIF(orient))
+	}
+	return b, nil
+}
+
+func stripWEBP(data []byte, gpsOnly bool) ([]byte, error) {
+	chunks, err := splitWEBP(data)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		out    []riffChunk
+		orient orientation
+	)
+	for _, c := range chunks {
+		switch {
+		case c.fourcc == "EXIF" && gpsOnly:
+			d := slices.Clone(c.data)
+			err := stripEXIFGPS(bytes.TrimPrefix(d, []byte(jpegEXIFHeader)))
+			if err != nil {
+				return nil, err
+			}
+			c.data = d
+		case c.fourcc == "EXIF":
+			orient = tiffOrientation(bytes.TrimPrefix(c.data, []byte(jpegEXIFHeader)))
+			continue
+		case c.fourcc == "XMP ":
+			if !gpsOnly {
+				continue
+			}
+			c.data = blankXMPGPS(c.data)
+		case c.fourcc == "VP8X" && !gpsOnly:
+			d := slices.Clone(c.data)
+			if len(d) > 0 {
+				d[0] &^= vp8xXMPFlag | vp8xEXIFFlag
+			}
+			c.data = d
+		}
+		out = append(out, c)
+	}
+	b := joinWEBP(out)
+	if orient > orientNormal {
+		return embedWEBPEXIF(b, orientationEXIF(orient))
+	}
+	return b, nil
+}
+
+const (
+	tiffOrientationTag = 274
+	tiffGPSIFDTag      = 0x8825
+	tiffInteropIFDTag  = 0xa005
+)
+
+// tiffImageTags are the baseline and extension tags that describe the image
+// data of a TIFF, which StripAll keeps.
+var tiffImageTags = []uint16{
+	254, 255, 256, 257, 258, 259, 262, 263, 264, 265, 266, 273, 274, 277,
+	278, 279, 280, 281, 282, 283, 284, 290, 291, 292, 293, 296, 297, 301,
+	317, 318, 319, 320, 321, 322, 323, 324, 325, 330, 332, 338, 339, 340,
+	341, 347, 512, 513, 514, 515, 517, 518, 519, 520, 521, 529, 530, 531,
+	532, 34675,
+}
+
+// stripTIFF removes the metadata entries of every IFD of a TIFF file, or
+// only the GPS IFD and the XMP location.
+func stripTIFF(data []byte, gpsOnly bool) ([]byte, error) {
+	b := slices.Clone(data)
+	bo, ifd, err := tiffHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	if gpsOnly {
+		err = blankTIFFXMPGPS(b, bo, ifd)
+		if err != nil {
+			return nil, err
+		}
+		_, err = scrubIFD(b, bo, ifd, func(tag uint16) bool {
+			return tag == tiffGPSIFDTag
+		})
+		return b, err
+	}
+	seen := map[int]bool{}
+	for ifd != 0 && !seen[ifd] {
+		seen[ifd] = true
+		ifd, err = scrubIFD(b, bo, ifd, func(tag uint16) bool {
+			return !slices.Contains(tiffImageTags, tag)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// stripEXIFGPS removes the GPS IFD from an EXIF block in place.
+func stripEXIFGPS(tiff []byte) error {
+	bo, ifd, err := tiffHeader(tiff)
+	if err != nil {
+		return err
+	}
+	_, err = scrubIFD(tiff, bo, ifd, func(tag uint16) bool {
+		return tag == tiffGPSIFDTag
+	})
+	return err
+}
+
+func tiffHeader(b []byte) (binary.ByteOrder, int, error) {
+	if len(b) < 8 {
+		return nil, 0, &CorruptError{Offset: 0, Err: errors.New("missing tiff header")}
+	}
+	var bo binary.ByteOrder
+	switch string(b[0:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return nil, 0, &CorruptError{Offset: 0, Err: errors.New("bad tiff byte order")}
+	}
+	return bo, int(bo.Uint32(b[4:])), nil
+}
+
+// tiffIFD checks that the IFD at off and the offset of the next IFD fit in b,
+// and returns its number of entries.
+func tiffIFD(b []byte, bo binary.ByteOrder, off int) (int, error) {
+	if off < 8 || off+2 > len(b) {
+		return 0, &CorruptError{Offset: int64(off), Err: errors.New("bad tiff ifd offset")}
+	}
+	n := int(bo.Uint16(b[off:]))
+	if off+2+n*12+4 > len(b) {
+		return 0, &CorruptError{Offset: int64(off), Err: errors.New("bad tiff ifd")}
+	}
+	return n, nil
+}
+
+func tiffTypeSize(typ uint16) int {
+	switch typ {
+	case 3, 8:
+		return 2
+	case 4, 9, 11, 13:
+		return 4
+	case 5, 10, 12:
+		return 8
+	}
+	return 1
+}
+
+// tiffEntryValue returns the bytes of an entry's value, which are inline if
+// they fit in the entry.
+func tiffEntryValue(b []byte, bo binary.ByteOrder, e int) []byte {
+	size := int(bo.Uint32(b[e+4:])) * tiffTypeSize(bo.Uint16(b[e+2:]))
+	if size <= 4 {
+		return b[e+8 : e+8+size]
+	}
+	off := int(bo.Uint32(b[e+8:]))
+	if size < 0 || off < 0 || off+size > len(b) {
+		return nil
+	}
+	return b[off : off+size]
+}
+
+// scrubIFD removes the entries of the IFD at off that drop reports, zeroing
+// their values and any IFDs they point to so nothing can be recovered. The
+// remaining entries are moved up in place. It returns the offset of the
+// next IFD.
+func scrubIFD(b []byte, bo binary.ByteOrder, off int, drop func(tag uint16) bool) (int, error) {
+	n, err := tiffIFD(b, bo, off)
+	if err != nil {
+		return 0, err
+	}
+	end := off + 2 + n*12
+	next := bo.Uint32(b[end:])
+	var kept [][]byte
+	for i := range n {
+		e := off + 2 + i*12
+		if !drop(bo.Uint16(b[e:])) {
+			kept = append(kept, slices.Clone(b[e:e+12]))
+			continue
+		}
+		zeroTIFFEntry(b, bo, e, 0)
+	}
+	clear(b[off : end+4])
+	bo.PutUint16(b[off:], uint16(len(kept)))
+	for i, e := range kept {
+		copy(b[off+2+i*12:], e)
+	}
+	bo.PutUint32(b[off+2+len(kept)*12:], next)
+	return int(next), nil
+}
+
+func zeroTIFFEntry(b []byte, bo binary.ByteOrder, e int, depth int) {
+	switch bo.Uint16(b[e:]) {
+	case exifIFDTag, tiffGPSIFDTag, tiffInteropIFDTag:
+		if depth < 2 {
+			zeroIFD(b, bo, int(bo.Uint32(b[e+8:])), depth+1)
+		}
+	}
+	clear(tiffEntryValue(b, bo, e))
+}
+
+func zeroIFD(b []byte, bo binary.ByteOrder, off int, depth int) {
+	n, err := tiffIFD(b, bo, off)
+	if err != nil {
+		return
+	}
+	for i := range n {
+		zeroTIFFEntry(b, bo, off+2+i*12, depth)
+	}
+	clear(b[off : off+2+n*12+4])
+}
+
+// tiffOrientation returns the orientation in IFD0 of an EXIF block.
+func tiffOrientation(tiff []byte) orientation {
+	return exifOrientation([]jpegSegment{{marker: 0xe1, data: append([]byte(jpegEXIFHeader), tiff...)}})
+}
+
+// orientationEXIF makes an EXIF block holding only the orientation.
+func orientationEXIF(o orientation) []byte {
+	b := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0}
+	b = binary.LittleEndian.AppendUint16(b, tiffOrientationTag)
+	b = binary.LittleEndian.AppendUint16(b, 3)
+	b = binary.LittleEndian.AppendUint32(b, 1)
+	b = binary.LittleEndian.AppendUint32(b, uint32(o))
+	return binary.LittleEndian.AppendUint32(b, 0)
+}
+
+var xmpGPS = regexp.MustCompile(`(?s)\s+exif:GPS\w+="[^"]*"|<exif:GPS\w+[^>]*/>|<exif:GPS\w+[^>]*>.*?</exif:GPS\w+>`)
+
+// blankXMPGPS overwrites the exif:GPS properties of an XMP packet with
+// spaces, keeping its length so it can be changed in place.
+func blankXMPGPS(packet []byte) []byte {
+	return xmpGPS.ReplaceAllFunc(packet, func(m []byte) []byte {
+		return bytes.Repeat([]byte(" "), len(m))
+	})
+}
+
+// blankTIFFXMPGPS blanks the location in the XMLPacket entry of an IFD in
+// place.
+func blankTIFFXMPGPS(b []byte, bo binary.ByteOrder, off int) error {
+	n, err := tiffIFD(b, bo, off)
+	if err != nil {
+		return err
+	}
+	for i := range n {
+		e := off + 2 + i*12
+		if bo.Uint16(b[e:]) == tiffXMPTag {
+			v := tiffEntryValue(b, bo, e)
+			copy(v, blankXMPGPS(v))
+		}
+	}
+	return nil
+}
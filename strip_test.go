@@ -0,0 +1,126 @@
+package img
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// testEXIF makes an EXIF block with a camera make, orientation and GPS map
+// datum.
+func testEXIF() []byte {
+	le := binary.LittleEndian
+	b := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	entry := func(tag, typ uint16, count, val uint32) {
+		b = le.AppendUint16(b, tag)
+		b = le.AppendUint16(b, typ)
+		b = le.AppendUint32(b, count)
+		b = le.AppendUint32(b, val)
+	}
+	b = le.AppendUint16(b, 3)
+	entry(0x010f, tiffASCII, 10, 50)
+	entry(tiffOrientationTag, 3, 1, uint32(orientRotate90))
+	entry(tiffGPSIFDTag, 4, 1, 60)
+	b = le.AppendUint32(b, 0)
+	b = append(b, "SECRETCAM\x00"...)
+	b = le.AppendUint16(b, 1)
+	entry(0x0012, tiffASCII, 12, 78)
+	b = le.AppendUint32(b, 0)
+	return append(b, "SECRETDATUM\x00"...)
+}
+
+func TestStripMeta(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	name := filepath.Join(dir, "photo.jpg")
+	var buf bytes.Buffer
+	c.Assert(jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16)), nil), qt.IsNil)
+	c.Assert(os.WriteFile(name, buf.Bytes(), 0644), qt.IsNil)
+
+	i, err := New(name)
+	c.Assert(err, qt.IsNil)
+	i.SetTitle("Title")
+	i.SetCreator("Ann")
+	i.SetRights("(c) Ann")
+	packet, err := i.xmpPacket()
+	c.Assert(err, qt.IsNil)
+	packet = bytes.Replace(packet, []byte("  </rdf:Description>"), []byte("   <exif:GPSLatitude>40,26.767N</exif:GPSLatitude>\n  </rdf:Description>"), 1)
+	b, err := i.embedMeta(JPEG, buf.Bytes())
+	c.Assert(err, qt.IsNil)
+	b, err = embedJPEGXMP(b, packet)
+	c.Assert(err, qt.IsNil)
+	b, err = embedEXIF(JPEG, b, testEXIF())
+	c.Assert(err, qt.IsNil)
+	c.Assert(os.WriteFile(name, b, 0644), qt.IsNil)
+	_, scan, err := splitJPEG(b)
+	c.Assert(err, qt.IsNil)
+
+	save := func(out string, p StripPolicy) ([]byte, *Img) {
+		i, err := New(name)
+		c.Assert(err, qt.IsNil)
+		c.Assert(i.ReadMeta(), qt.IsNil)
+		out = filepath.Join(dir, out)
+		c.Assert(i.SaveAs(out, StripMeta(p)), qt.IsNil)
+		b, err := os.ReadFile(out)
+		c.Assert(err, qt.IsNil)
+		segs, s, err := splitJPEG(b)
+		c.Assert(err, qt.IsNil)
+		// the pixels aren't re-encoded
+		c.Assert(s, qt.DeepEquals, scan)
+		// the orientation is always kept
+		c.Assert(exifOrientation(segs), qt.Equals, orientRotate90)
+		saved, err := New(out)
+		c.Assert(err, qt.IsNil)
+		c.Assert(saved.ReadMeta(), qt.IsNil)
+		return b, saved
+	}
+
+	b, saved := save("gps.jpg", StripGPS)
+	c.Assert(bytes.Contains(b, []byte("SECRETDATUM")), qt.IsFalse)
+	c.Assert(bytes.Contains(b, []byte("GPSLatitude")), qt.IsFalse)
+	c.Assert(bytes.Contains(b, []byte("SECRETCAM")), qt.IsTrue)
+	c.Assert(saved.DublinCore().Title, qt.DeepEquals, []string{"Title"})
+
+	b, saved = save("all.jpg", StripAll)
+	for _, s := range []string{"SECRETCAM", "SECRETDATUM", "Ann", jpegXMPHeader, jpegIPTCHeader} {
+		c.Assert(bytes.Contains(b, []byte(s)), qt.IsFalse, qt.Commentf(s))
+	}
+	c.Assert(saved.hasDC(), qt.IsFalse)
+
+	b, saved = save("keep.jpg", KeepCopyright)
+	c.Assert(bytes.Contains(b, []byte("SECRETCAM")), qt.IsFalse)
+	c.Assert(saved.DublinCore().Creator, qt.DeepEquals, []string{"Ann"})
+	c.Assert(saved.DublinCore().Rights, qt.DeepEquals, []string{"(c) Ann"})
+	c.Assert(saved.DublinCore().Title, qt.HasLen, 0)
+
+	// other formats
+	m := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for _, f := range []Format{PNG, WEBP, TIFF} {
+		buf.Reset()
+		c.Assert(NewEncoder(f).Encode(&buf, m), qt.IsNil)
+		b, err := i.embedMeta(f, buf.Bytes())
+		c.Assert(err, qt.IsNil)
+		if canEmbedEXIF(f) {
+			b, err = embedEXIF(f, b, testEXIF())
+			c.Assert(err, qt.IsNil)
+		}
+		gps, err := stripMeta(f, b, StripGPS)
+		c.Assert(err, qt.IsNil)
+		c.Assert(bytes.Contains(gps, []byte("SECRETDATUM")), qt.IsFalse, qt.Commentf("%v", f))
+		c.Assert(bytes.Contains(gps, []byte("Ann")), qt.IsTrue, qt.Commentf("%v", f))
+
+		all, err := stripMeta(f, b, StripAll)
+		c.Assert(err, qt.IsNil)
+		for _, s := range []string{"SECRETCAM", "Ann", "Title"} {
+			c.Assert(bytes.Contains(all, []byte(s)), qt.IsFalse, qt.Commentf("%v %s", f, s))
+		}
+		_, err = f.Decode(bytes.NewReader(all))
+		c.Assert(err, qt.IsNil, qt.Commentf("%v", f))
+	}
+}
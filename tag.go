@@ -2,6 +2,9 @@ package img
 
 import (
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/samber/lo"
@@ -34,6 +37,71 @@ func (t *Tags) UnmarshalXMP(d []byte) error {
 	return xml.Unmarshal(d, t)
 }
 
+// MarshalXMP encodes the tree as Categories XML.
+func (t *Tags) MarshalXMP() ([]byte, error) {
+	return xml.Marshal(t)
+}
+
+// MarshalField encodes the tree in the dialect of a tag field: Categories
+// XML, paths joined with the field's Sep for HierarchicalSubject, CatalogSets,
+// LastKeywordXMP and TagsList, or every tag name for the flat Subject and
+// Keywords.
+func (t *Tags) MarshalField(f ExifField) ([]string, error) {
+	switch {
+	case f == Categories:
+		d, err := t.MarshalXMP()
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(d)}, nil
+	case f.Sep() != "":
+		return t.Join(f.Sep()), nil
+	case f == Subject, f == Keywords:
+		return t.StringSlice(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotTagField, f)
+}
+
+// UnmarshalField decodes values written in the dialect of a tag field. See
+// MarshalField.
+func UnmarshalField(f ExifField, vals []string) (*Tags, error) {
+	switch {
+	case f == Categories:
+		tags := NewTags()
+		for _, v := range vals {
+			cat, err := UnmarshalHTags([]byte(v))
+			if err != nil {
+				return tags, err
+			}
+			for _, p := range cat.Paths() {
+				tags.Add(p...)
+			}
+		}
+		return tags, nil
+	case f.Sep() != "":
+		return FromPaths(vals, f.Sep()), nil
+	case f == Subject, f == Keywords:
+		tags := NewTags()
+		for _, v := range vals {
+			if v = strings.TrimSpace(v); v != "" {
+				tags.Add(v)
+			}
+		}
+		return tags, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotTagField, f)
+}
+
+// Join returns the path to every leaf tag joined with sep, eg Separator.
+func (t *Tags) Join(sep string) []string {
+	paths := t.Paths()
+	joined := make([]string, len(paths))
+	for i, p := range paths {
+		joined[i] = strings.Join(p, sep)
+	}
+	return joined
+}
+
 func (t *Tags) StringSlice() []string {
 	tags := [][]string{}
 	for _, c := range t.Children {
@@ -47,6 +115,162 @@ func (t *Tags) IsEmpty() bool {
 	return len(t.Children) == 0
 }
 
+// SkipChildren is returned from a WalkFunc to skip the children of the
+// current tag.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called by Tags.Walk with the path from the root to each tag.
+type WalkFunc func(path []string, tag *Tag) error
+
+// FromPaths builds a tree from hierarchical keywords such as "A > B > C",
+// "A|B|C" or "A/B/C". An empty sep splits on any of the separators.
+func FromPaths(paths []string, sep string) *Tags {
+	tags := NewTags()
+	for _, p := range paths {
+		tags.Add(splitPath(p, sep)...)
+	}
+	return tags
+}
+
+func splitPath(p, sep string) []string {
+	var parts []string
+	if sep == "" {
+		parts = SplitTags(p)
+	} else {
+		parts = strings.Split(p, sep)
+	}
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			path = append(path, part)
+		}
+	}
+	return path
+}
+
+// Paths returns the path from the root to every leaf tag.
+func (t *Tags) Paths() [][]string {
+	var paths [][]string
+	t.Walk(func(path []string, tag *Tag) error {
+		if len(tag.Children) == 0 {
+			paths = append(paths, slices.Clone(path))
+		}
+		return nil
+	})
+	return paths
+}
+
+// Add inserts path into the tree, creating any missing parents.
+func (t *Tags) Add(path ...string) {
+	children := &t.Children
+	for _, name := range path {
+		tag := findChild(*children, name)
+		if tag == nil {
+			tag = &Tag{Value: name}
+			*children = append(*children, tag)
+		}
+		children = &tag.Children
+	}
+}
+
+// Remove deletes the tag at path along with its children. It reports whether
+// the tag was found.
+func (t *Tags) Remove(path ...string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	children := &t.Children
+	for _, name := range path[:len(path)-1] {
+		tag := findChild(*children, name)
+		if tag == nil {
+			return false
+		}
+		children = &tag.Children
+	}
+	last := path[len(path)-1]
+	i := slices.IndexFunc(*children, func(tag *Tag) bool {
+		return tag.Value == last
+	})
+	if i < 0 {
+		return false
+	}
+	*children = slices.Delete(*children, i, i+1)
+	return true
+}
+
+// Rename moves the tag at from, along with its children, to the path to,
+// merging it with any tag already there. It reports whether from was found.
+func (t *Tags) Rename(from, to []string) bool {
+	if len(from) == 0 || len(to) == 0 {
+		return false
+	}
+	var sub [][]string
+	found := false
+	t.Walk(func(path []string, tag *Tag) error {
+		if len(path) < len(from) || !slices.Equal(path[:len(from)], from) {
+			return nil
+		}
+		found = true
+		if len(tag.Children) == 0 {
+			sub = append(sub, slices.Clone(path[len(from):]))
+		}
+		return nil
+	})
+	if !found {
+		return false
+	}
+	t.Remove(from...)
+	for _, p := range sub {
+		t.Add(append(slices.Clone(to), p...)...)
+	}
+	return true
+}
+
+// Find returns the path to every tag named name, at any depth.
+func (t *Tags) Find(name string) [][]string {
+	var paths [][]string
+	t.Walk(func(path []string, tag *Tag) error {
+		if strings.EqualFold(tag.Value, name) {
+			paths = append(paths, slices.Clone(path))
+		}
+		return nil
+	})
+	return paths
+}
+
+// Walk calls fn for every tag in depth first order, parents before their
+// children. The path passed to fn is reused between calls.
+func (t *Tags) Walk(fn WalkFunc) error {
+	return walkTags(t.Children, nil, fn)
+}
+
+func walkTags(tags []*Tag, path []string, fn WalkFunc) error {
+	for _, tag := range tags {
+		p := append(path, tag.Value)
+		err := fn(p, tag)
+		if errors.Is(err, SkipChildren) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = walkTags(tag.Children, p, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findChild(tags []*Tag, name string) *Tag {
+	for _, tag := range tags {
+		if tag.Value == name {
+			return tag
+		}
+	}
+	return nil
+}
+
 func walkChildren(t *Tag) []string {
 	if t == nil {
 		return []string{}
@@ -0,0 +1,75 @@
+package img
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestTagPaths(t *testing.T) {
+	c := qt.New(t)
+	want := [][]string{
+		{"Places", "USA", "New York"},
+		{"Places", "France"},
+		{"People"},
+	}
+	for _, sep := range []string{Separator, barSep, slashSep} {
+		tags := FromPaths([]string{
+			"Places" + sep + "USA" + sep + "New York",
+			"Places" + sep + "France",
+			"People",
+		}, sep)
+		c.Assert(tags.Paths(), qt.DeepEquals, want)
+	}
+	mixed := FromPaths([]string{"Places > USA > New York", "Places|France", "People"}, "")
+	c.Assert(mixed.Paths(), qt.DeepEquals, want)
+
+	tags := FromPaths([]string{"A/B/C", "A/B/D", "E/C"}, slashSep)
+	c.Assert(tags.Find("c"), qt.DeepEquals, [][]string{{"A", "B", "C"}, {"E", "C"}})
+
+	c.Assert(tags.Remove("A", "B", "C"), qt.IsTrue)
+	c.Assert(tags.Remove("A", "X"), qt.IsFalse)
+	c.Assert(tags.Paths(), qt.DeepEquals, [][]string{{"A", "B", "D"}, {"E", "C"}})
+
+	tags.Add("A", "F")
+	var visited []string
+	err := tags.Walk(func(path []string, tag *Tag) error {
+		visited = append(visited, tag.Value)
+		if tag.Value == "B" {
+			return SkipChildren
+		}
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(visited, qt.DeepEquals, []string{"A", "B", "F", "E", "C"})
+
+	c.Assert(tags.Rename([]string{"A", "B"}, []string{"E"}), qt.IsTrue)
+	c.Assert(tags.Rename([]string{"X"}, []string{"Y"}), qt.IsFalse)
+	c.Assert(tags.Paths(), qt.DeepEquals, [][]string{{"A", "F"}, {"E", "C"}, {"E", "D"}})
+}
+
+func TestTagFields(t *testing.T) {
+	c := qt.New(t)
+	tags := FromPaths([]string{"Places > USA > New York", "People > Friends"}, Separator)
+
+	for _, f := range []ExifField{Categories, HierarchicalSubject, LastKeywordXMP, CatalogSets, TagsList} {
+		vals, err := tags.MarshalField(f)
+		c.Assert(err, qt.IsNil)
+		got, err := UnmarshalField(f, vals)
+		c.Assert(err, qt.IsNil)
+		c.Assert(got.Paths(), qt.DeepEquals, tags.Paths(), qt.Commentf("%s: %v", f, vals))
+	}
+
+	vals, err := tags.MarshalField(HierarchicalSubject)
+	c.Assert(err, qt.IsNil)
+	c.Assert(vals, qt.DeepEquals, []string{"Places|USA|New York", "People|Friends"})
+
+	vals, err = tags.MarshalField(Subject)
+	c.Assert(err, qt.IsNil)
+	c.Assert(vals, qt.DeepEquals, []string{"Places", "USA", "New York", "People", "Friends"})
+
+	c.Assert(tags.Join(Separator), qt.DeepEquals, []string{"Places > USA > New York", "People > Friends"})
+
+	_, err = tags.MarshalField(Title)
+	c.Assert(err, qt.ErrorIs, ErrNotTagField)
+}
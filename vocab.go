@@ -0,0 +1,121 @@
+package img
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Vocabulary is a controlled tree of canonical keywords.
+type Vocabulary struct {
+	Keywords []*Keyword `json:"keywords" yaml:"keywords"`
+	index    map[string][]string
+}
+
+// Keyword is a canonical keyword, the aliases that should be replaced by it
+// and its child keywords.
+type Keyword struct {
+	Name     string     `json:"name" yaml:"name"`
+	Aliases  []string   `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Children []*Keyword `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// OpenVocabulary loads a vocabulary from a YAML file.
+func OpenVocabulary(name string) (*Vocabulary, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadVocabulary(f)
+}
+
+// LoadVocabulary reads a vocabulary from YAML such as:
+//
+//	keywords:
+//	  - name: Places
+//	    children:
+//	      - name: New York City
+//	        aliases: [NYC]
+func LoadVocabulary(r io.Reader) (*Vocabulary, error) {
+	v := &Vocabulary{}
+	err := yaml.NewDecoder(r).Decode(v)
+	if err != nil {
+		return nil, err
+	}
+	err = v.buildIndex()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Vocabulary) buildIndex() error {
+	v.index = make(map[string][]string)
+	return v.indexKeywords(v.Keywords, nil)
+}
+
+func (v *Vocabulary) indexKeywords(kws []*Keyword, parent []string) error {
+	for _, kw := range kws {
+		path := append(slices.Clone(parent), kw.Name)
+		for _, name := range append([]string{kw.Name}, kw.Aliases...) {
+			key := vocabKey(name)
+			if p, ok := v.index[key]; ok && !slices.Equal(p, path) {
+				return fmt.Errorf("vocabulary: %q is used by both %q and %q", name, strings.Join(p, Separator), strings.Join(path, Separator))
+			}
+			v.index[key] = path
+		}
+		err := v.indexKeywords(kw.Children, path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the canonical path for a keyword or alias, ignoring case and
+// extra spaces. An error is returned if two keywords share a name or alias.
+func (v *Vocabulary) Lookup(name string) ([]string, bool, error) {
+	if v.index == nil {
+		err := v.buildIndex()
+		if err != nil {
+			v.index = nil
+			return nil, false, err
+		}
+	}
+	p, ok := v.index[vocabKey(name)]
+	return slices.Clone(p), ok, nil
+}
+
+func vocabKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Normalize replaces every leaf tag with its canonical path in the
+// vocabulary, adding any missing parents. Tags that aren't in the vocabulary
+// are kept with their spacing tidied and are returned joined with Separator.
+func (t *Tags) Normalize(v *Vocabulary) ([]string, error) {
+	var unknown []string
+	norm := NewTags()
+	for _, p := range t.Paths() {
+		canon, ok, err := v.Lookup(p[len(p)-1])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			norm.Add(canon...)
+			continue
+		}
+		for i := range p {
+			p[i] = strings.Join(strings.Fields(p[i]), " ")
+		}
+		norm.Add(p...)
+		unknown = append(unknown, strings.Join(p, Separator))
+	}
+	t.Children = norm.Children
+	return unknown, nil
+}
@@ -0,0 +1,57 @@
+package img
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const testVocab = `
+keywords:
+  - name: Places
+    children:
+      - name: USA
+        aliases: [United States]
+        children:
+          - name: New York City
+            aliases: [NYC, New York]
+  - name: People
+`
+
+func TestNormalize(t *testing.T) {
+	c := qt.New(t)
+	v, err := LoadVocabulary(strings.NewReader(testVocab))
+	c.Assert(err, qt.IsNil)
+
+	p, ok, err := v.Lookup("  nyc ")
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(p, qt.DeepEquals, []string{"Places", "USA", "New York City"})
+
+	tags := FromPaths([]string{"NYC", "Travel >  Summer", "people"}, "")
+	unknown, err := tags.Normalize(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(unknown, qt.DeepEquals, []string{"Travel > Summer"})
+	c.Assert(tags.Join(Separator), qt.DeepEquals, []string{
+		"Places > USA > New York City",
+		"Travel > Summer",
+		"People",
+	})
+
+	_, err = LoadVocabulary(strings.NewReader(`
+keywords:
+  - name: A
+    aliases: [x]
+  - name: B
+    aliases: [X]
+`))
+	c.Assert(err, qt.IsNotNil)
+
+	// a vocabulary built in code is indexed on first use
+	v = &Vocabulary{Keywords: []*Keyword{{Name: "A"}, {Name: "B", Aliases: []string{"a"}}}}
+	_, _, err = v.Lookup("A")
+	c.Assert(err, qt.ErrorMatches, `vocabulary: "a" is used by both "A" and "B"`)
+	_, err = FromPaths([]string{"A"}, "").Normalize(v)
+	c.Assert(err, qt.IsNotNil)
+}
@@ -0,0 +1,383 @@
+package img
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evanoberholster/imagemeta/xmp"
+)
+
+// XMP namespaces of the properties read and written by this package.
+const (
+	nsX         = "adobe:ns:meta/"
+	nsRDF       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	nsDC        = "http://purl.org/dc/elements/1.1/"
+	nsLR        = "http://ns.adobe.com/lightroom/1.0/"
+	nsDigiKam   = "http://www.digikam.org/ns/1.0/"
+	nsMSPhoto   = "http://ns.microsoft.com/photo/1.0/"
+	nsMediaPro  = "http://ns.iview-multimedia.com/mediapro/1.0/"
+	nsACDSee    = "http://ns.acdsee.com/iptc/1.0/"
+	nsPhotoshop = "http://ns.adobe.com/photoshop/1.0/"
+	nsXMPMM     = "http://ns.adobe.com/xap/1.0/mm/"
+	nsXMPBasic  = "http://ns.adobe.com/xap/1.0/"
+	nsEXIF      = "http://ns.adobe.com/exif/1.0/"
+)
+
+var xmpPrefixes = []struct {
+	prefix string
+	ns     string
+}{
+	{"dc", nsDC},
+	{"lr", nsLR},
+	{"digiKam", nsDigiKam},
+	{"MicrosoftPhoto", nsMSPhoto},
+	{"MediaPro", nsMediaPro},
+	{"acdsee", nsACDSee},
+	{"photoshop", nsPhotoshop},
+	{"xmpMM", nsXMPMM},
+}
+
+func xmpPrefix(ns string) string {
+	for _, p := range xmpPrefixes {
+		if p.ns == ns {
+			return p.prefix
+		}
+	}
+	return ""
+}
+
+// xmpName returns the XMP property that holds the field, if any.
+func (f ExifField) xmpName() (xml.Name, bool) {
+	switch f {
+	case Title:
+		return xml.Name{Space: nsDC, Local: "title"}, true
+	case Description:
+		return xml.Name{Space: nsDC, Local: "description"}, true
+	case Rights:
+		return xml.Name{Space: nsDC, Local: "rights"}, true
+	case Subject:
+		return xml.Name{Space: nsDC, Local: "subject"}, true
+	case Byline:
+		return xml.Name{Space: nsDC, Local: "creator"}, true
+	case Caption:
+		return xml.Name{Space: nsACDSee, Local: "caption"}, true
+	case Credit:
+		return xml.Name{Space: nsPhotoshop, Local: "Credit"}, true
+	case Categories:
+		return xml.Name{Space: nsACDSee, Local: "categories"}, true
+	case HierarchicalSubject:
+		return xml.Name{Space: nsLR, Local: "hierarchicalSubject"}, true
+	case TagsList:
+		return xml.Name{Space: nsDigiKam, Local: "TagsList"}, true
+	case LastKeywordXMP:
+		return xml.Name{Space: nsMSPhoto, Local: "LastKeywordXMP"}, true
+	case CatalogSets:
+		return xml.Name{Space: nsMediaPro, Local: "CatalogSets"}, true
+	}
+	return xml.Name{}, false
+}
+
+var xmpDocumentID = xml.Name{Space: nsXMPMM, Local: "DocumentID"}
+
+// xmpCaptureTimes are the properties that hold when a photo was taken, in
+// order of preference.
+var xmpCaptureTimes = []xml.Name{
+	{Space: nsEXIF, Local: "DateTimeOriginal"},
+	{Space: nsPhotoshop, Local: "DateCreated"},
+	{Space: nsXMPBasic, Local: "CreateDate"},
+}
+
+// xmpDateLayouts are the forms of an XMP date, from most to least precise.
+var xmpDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// captureTime returns the first capture time that parses.
+func (p xmpProps) captureTime() time.Time {
+	for _, name := range xmpCaptureTimes {
+		for _, v := range p[name] {
+			for _, layout := range xmpDateLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	xmpGPSLatitude  = xml.Name{Space: nsEXIF, Local: "GPSLatitude"}
+	xmpGPSLongitude = xml.Name{Space: nsEXIF, Local: "GPSLongitude"}
+)
+
+// gps returns the location in decimal degrees.
+func (p xmpProps) gps() (lat, long float64) {
+	if v := p[xmpGPSLatitude]; len(v) > 0 {
+		lat = parseXMPCoord(v[0])
+	}
+	if v := p[xmpGPSLongitude]; len(v) > 0 {
+		long = parseXMPCoord(v[0])
+	}
+	return lat, long
+}
+
+// parseXMPCoord parses an XMP GPS coordinate, written as "DDD,MM,SSk" or
+// "DDD,MM.mmk" where k is N, S, E or W. It returns 0 if s is malformed.
+func parseXMPCoord(s string) float64 {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return 0
+	}
+	sign := 1.0
+	switch s[len(s)-1] {
+	case 'N', 'E':
+	case 'S', 'W':
+		sign = -1
+	default:
+		return 0
+	}
+	parts := strings.Split(s[:len(s)-1], ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+	v, div := 0.0, 1.0
+	for _, part := range parts {
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		v += f / div
+		div *= 60
+	}
+	return sign * v
+}
+
+// xmpArray is the kind of RDF container used for a property.
+type xmpArray int
+
+const (
+	xmpSimple xmpArray = iota
+	xmpAlt
+	xmpSeq
+	xmpBag
+)
+
+func (a xmpArray) String() string {
+	switch a {
+	case xmpAlt:
+		return "Alt"
+	case xmpSeq:
+		return "Seq"
+	case xmpBag:
+		return "Bag"
+	}
+	return ""
+}
+
+func (f ExifField) xmpArray() xmpArray {
+	switch f {
+	case Title, Description, Rights:
+		return xmpAlt
+	case Byline, TagsList:
+		return xmpSeq
+	case Subject, HierarchicalSubject, LastKeywordXMP, CatalogSets:
+		return xmpBag
+	}
+	return xmpSimple
+}
+
+// xmpProps holds the values of the top level properties of an XMP packet.
+// Array items are kept in order and simple values have a single item.
+type xmpProps map[xml.Name][]string
+
+func (p xmpProps) get(f ExifField) []string {
+	name, ok := f.xmpName()
+	if !ok {
+		return nil
+	}
+	return p[name]
+}
+
+// meta returns the Dublin Core and tag tree held by the properties. The title
+// and creator fall back to the ACDSee caption and Photoshop credit, and the
+// tags are merged from every hierarchical keyword dialect, falling back to
+// the flat dc:subject.
+func (p xmpProps) meta() (xmp.XMP, *Tags, error) {
+	x := xmp.XMP{DC: xmp.DublinCore{}}
+	x.DC.Title = firstProp(p, Title, Caption)
+	x.DC.Creator = firstProp(p, Byline, Credit)
+	x.DC.Description = p.get(Description)
+	x.DC.Rights = p.get(Rights)
+
+	tags := NewTags()
+	for _, f := range hTagFields {
+		vals := p.get(f)
+		if len(vals) == 0 {
+			continue
+		}
+		t, err := UnmarshalField(f, vals)
+		if err != nil {
+			return x, tags, &CorruptError{Offset: -1, Err: err}
+		}
+		for _, path := range t.Paths() {
+			tags.Add(path...)
+		}
+	}
+	if tags.IsEmpty() {
+		for _, s := range p.get(Subject) {
+			tags.Add(s)
+		}
+	}
+	x.DC.Subject = tags.StringSlice()
+	return x, tags, nil
+}
+
+// firstProp returns the values of the first field that is set.
+func firstProp(props xmpProps, fields ...ExifField) []string {
+	for _, f := range fields {
+		if vals := props.get(f); len(vals) > 0 {
+			return vals
+		}
+	}
+	return nil
+}
+
+// dcIsEmpty reports whether none of the Dublin Core this package writes is
+// set.
+func dcIsEmpty(dc xmp.DublinCore) bool {
+	return len(dc.Title)+len(dc.Creator)+len(dc.Description)+len(dc.Rights)+len(dc.Subject) == 0
+}
+
+// parseXMP reads the top level properties of an XMP packet, whether they are
+// written as attributes of rdf:Description or as elements.
+func parseXMP(r io.Reader) (xmpProps, error) {
+	props := xmpProps{}
+	d := xml.NewDecoder(r)
+	var (
+		stack   []xml.Name
+		prop    xml.Name
+		propLvl int
+		inLi    bool
+		hasLi   bool
+		text    strings.Builder
+	)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return props, nil
+		}
+		if err != nil {
+			return props, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := xml.Name{}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			stack = append(stack, t.Name)
+			switch {
+			case t.Name == xml.Name{Space: nsRDF, Local: "Description"} && parent == xml.Name{Space: nsRDF, Local: "RDF"}:
+				for _, attr := range t.Attr {
+					if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == nsRDF {
+						continue
+					}
+					props[attr.Name] = []string{attr.Value}
+				}
+			case parent == xml.Name{Space: nsRDF, Local: "Description"} && propLvl == 0:
+				prop = t.Name
+				propLvl = len(stack)
+				hasLi = false
+				text.Reset()
+			case propLvl > 0 && t.Name == xml.Name{Space: nsRDF, Local: "li"} && len(stack) == propLvl+2:
+				inLi = true
+				hasLi = true
+				text.Reset()
+			}
+		case xml.CharData:
+			if propLvl > 0 && (inLi || len(stack) == propLvl) {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case inLi && len(stack) == propLvl+2:
+				inLi = false
+				if v := strings.TrimSpace(text.String()); v != "" {
+					props[prop] = append(props[prop], v)
+				}
+			case propLvl > 0 && len(stack) == propLvl:
+				if v := strings.TrimSpace(text.String()); !hasLi && v != "" {
+					props[prop] = []string{v}
+				}
+				propLvl = 0
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// xmpProp is a property written to an XMP packet.
+type xmpProp struct {
+	name   xml.Name
+	array  xmpArray
+	values []string
+}
+
+// marshalXMP writes a standalone XMP packet with the given properties.
+func marshalXMP(props []xmpProp) []byte {
+	var b bytes.Buffer
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="` + nsX + `">` + "\n")
+	b.WriteString(` <rdf:RDF xmlns:rdf="` + nsRDF + `">` + "\n")
+	b.WriteString(`  <rdf:Description rdf:about=""`)
+	for _, p := range xmpPrefixes {
+		b.WriteString("\n    xmlns:" + p.prefix + `="` + p.ns + `"`)
+	}
+	b.WriteString(">\n")
+	for _, p := range props {
+		if len(p.values) == 0 {
+			continue
+		}
+		tag := xmpPrefix(p.name.Space) + ":" + p.name.Local
+		b.WriteString("   <" + tag + ">")
+		if p.array == xmpSimple {
+			xml.EscapeText(&b, []byte(p.values[0]))
+			b.WriteString("</" + tag + ">\n")
+			continue
+		}
+		b.WriteString("\n    <rdf:" + p.array.String() + ">\n")
+		for _, v := range p.values {
+			b.WriteString("     <rdf:li")
+			if p.array == xmpAlt {
+				b.WriteString(` xml:lang="x-default"`)
+			}
+			b.WriteString(">")
+			xml.EscapeText(&b, []byte(v))
+			b.WriteString("</rdf:li>\n")
+		}
+		b.WriteString("    </rdf:" + p.array.String() + ">\n")
+		b.WriteString("   </" + tag + ">\n")
+	}
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString(`<?xpacket end="w"?>`)
+	return b.Bytes()
+}
+
+// fieldProp makes a property for an ExifField.
+func fieldProp(f ExifField, values ...string) xmpProp {
+	name, _ := f.xmpName()
+	return xmpProp{name: name, array: f.xmpArray(), values: values}
+}
@@ -0,0 +1,121 @@
+package img
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseXMP(t *testing.T) {
+	c := qt.New(t)
+	packet := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+ <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Description rdf:about=""
+    xmlns:dc="http://purl.org/dc/elements/1.1/"
+    xmlns:photoshop="http://ns.adobe.com/photoshop/1.0/"
+    photoshop:Credit="Ann">
+   <dc:title><rdf:Alt><rdf:li xml:lang="x-default">Bridge &amp; River</rdf:li></rdf:Alt></dc:title>
+   <dc:subject><rdf:Bag><rdf:li>a</rdf:li><rdf:li>b</rdf:li></rdf:Bag></dc:subject>
+  </rdf:Description>
+ </rdf:RDF>
+</x:xmpmeta>`
+	props, err := parseXMP(strings.NewReader(packet))
+	c.Assert(err, qt.IsNil)
+	c.Assert(props.get(Title), qt.DeepEquals, []string{"Bridge & River"})
+	c.Assert(props.get(Subject), qt.DeepEquals, []string{"a", "b"})
+	c.Assert(props.get(Credit), qt.DeepEquals, []string{"Ann"})
+
+	out := marshalXMP([]xmpProp{
+		fieldProp(Title, "<Title>"),
+		fieldProp(HierarchicalSubject, "A|B", "C"),
+	})
+	props, err = parseXMP(bytes.NewReader(out))
+	c.Assert(err, qt.IsNil)
+	c.Assert(props.get(Title), qt.DeepEquals, []string{"<Title>"})
+	c.Assert(props.get(HierarchicalSubject), qt.DeepEquals, []string{"A|B", "C"})
+}
+
+func TestEmbedXMP(t *testing.T) {
+	c := qt.New(t)
+	for _, f := range []Format{JPEG, PNG, WEBP, TIFF} {
+		c.Run(f.String(), func(c *qt.C) {
+			src := FromImage(image.NewNRGBA(image.Rect(0, 0, 12, 8)), f)
+			src.SetTitle("Bridge")
+			src.SetCreator("Ann", "Bob")
+			src.SetTags(FromPaths([]string{"Places > NYC", "People"}, ""))
+			data, err := src.encodeWithMeta(NewEncoder(f))
+			c.Assert(err, qt.IsNil)
+
+			i, err := FromBytes(data)
+			c.Assert(err, qt.IsNil)
+			c.Assert(i.img.Bounds().Dx(), qt.Equals, 12)
+			dc := i.DublinCore()
+			c.Assert(dc.Title, qt.DeepEquals, []string{"Bridge"})
+			c.Assert(dc.Creator, qt.DeepEquals, []string{"Ann", "Bob"})
+			c.Assert(i.Tags().Paths(), qt.DeepEquals, [][]string{{"Places", "NYC"}, {"People"}})
+
+			// embedding again replaces the packet
+			i.ClearMeta()
+			i.SetTitle("Other")
+			again, err := i.embedMeta(f, data)
+			c.Assert(err, qt.IsNil)
+			i, err = FromBytes(again)
+			c.Assert(err, qt.IsNil)
+			c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Other"})
+			c.Assert(i.Tags().IsEmpty(), qt.IsTrue)
+		})
+	}
+	_, err := embedXMP(GIF, nil, nil)
+	c.Assert(err, qt.ErrorIs, ErrUnsupportedFormat)
+}
+
+func TestSidecar(t *testing.T) {
+	c := qt.New(t)
+	src := FromImage(image.NewNRGBA(image.Rect(0, 0, 4, 4)), PNG)
+	src.SetTitle("Embedded")
+	data, err := src.encodeWithMeta(NewEncoder(PNG))
+	c.Assert(err, qt.IsNil)
+
+	side := FromImage(image.NewNRGBA(image.Rect(0, 0, 4, 4)), PNG)
+	side.SetTitle("Sidecar")
+	side.SetCreator("Ann")
+	var packet bytes.Buffer
+	c.Assert(side.EncodeXMP(&packet), qt.IsNil)
+	c.Assert(packet.String(), qt.Contains, "<x:xmpmeta")
+
+	fsys := fstest.MapFS{
+		"photo.png": {Data: data},
+		"photo.xmp": {Data: packet.Bytes()},
+	}
+	i, err := OpenFS(fsys, "photo.png")
+	c.Assert(err, qt.IsNil)
+	c.Assert(i.DublinCore().Creator, qt.HasLen, 0)
+
+	c.Assert(i.ReadMeta(Sidecar(PreferEmbedded)), qt.IsNil)
+	c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Embedded"})
+	c.Assert(i.DublinCore().Creator, qt.DeepEquals, []string{"Ann"})
+
+	c.Assert(i.ReadMeta(Sidecar(PreferSidecar)), qt.IsNil)
+	c.Assert(i.DublinCore().Title, qt.DeepEquals, []string{"Sidecar"})
+
+	// the sidecar is written to the image's file system
+	c.Assert(i.WriteSidecar(), qt.ErrorIs, errors.ErrUnsupported)
+	dir := t.TempDir()
+	c.Assert(os.WriteFile(filepath.Join(dir, "photo.png"), data, 0644), qt.IsNil)
+	i, err = OpenFS(DirFS(dir), "photo.png")
+	c.Assert(err, qt.IsNil)
+	i.SetTitle("Sidecar")
+	c.Assert(i.WriteSidecar(), qt.IsNil)
+	b, err := os.ReadFile(filepath.Join(dir, "photo.xmp"))
+	c.Assert(err, qt.IsNil)
+	props, err := parseXMP(bytes.NewReader(b))
+	c.Assert(err, qt.IsNil)
+	c.Assert(props.get(Title), qt.DeepEquals, []string{"Sidecar"})
+}